@@ -0,0 +1,51 @@
+package env
+
+import (
+	"context"
+	"time"
+)
+
+// GetString is a non-generic shorthand for GetOrDefault with a string destination.
+func (p *Parser) GetString(ctx context.Context, envVar string, defaultVal string, opts ...EnvParseOption) (string, error) {
+	return GetOrDefault(ctx, p, envVar, defaultVal, opts...)
+}
+
+// GetBool is a non-generic shorthand for GetOrDefault with a bool destination.
+func (p *Parser) GetBool(ctx context.Context, envVar string, defaultVal bool, opts ...EnvParseOption) (bool, error) {
+	return GetOrDefault(ctx, p, envVar, defaultVal, opts...)
+}
+
+// GetInt is a non-generic shorthand for GetOrDefault with an int destination.
+func (p *Parser) GetInt(ctx context.Context, envVar string, defaultVal int, opts ...EnvParseOption) (int, error) {
+	return GetOrDefault(ctx, p, envVar, defaultVal, opts...)
+}
+
+// GetInt64 is a non-generic shorthand for GetOrDefault with an int64 destination.
+func (p *Parser) GetInt64(ctx context.Context, envVar string, defaultVal int64, opts ...EnvParseOption) (int64, error) {
+	return GetOrDefault(ctx, p, envVar, defaultVal, opts...)
+}
+
+// GetUint64 is a non-generic shorthand for GetOrDefault with a uint64 destination.
+func (p *Parser) GetUint64(ctx context.Context, envVar string, defaultVal uint64, opts ...EnvParseOption) (uint64, error) {
+	return GetOrDefault(ctx, p, envVar, defaultVal, opts...)
+}
+
+// GetFloat64 is a non-generic shorthand for GetOrDefault with a float64 destination.
+func (p *Parser) GetFloat64(ctx context.Context, envVar string, defaultVal float64, opts ...EnvParseOption) (float64, error) {
+	return GetOrDefault(ctx, p, envVar, defaultVal, opts...)
+}
+
+// GetDuration is a non-generic shorthand for GetOrDefault with a time.Duration destination.
+func (p *Parser) GetDuration(ctx context.Context, envVar string, defaultVal time.Duration, opts ...EnvParseOption) (time.Duration, error) {
+	return GetOrDefault(ctx, p, envVar, defaultVal, opts...)
+}
+
+// GetTime is a non-generic shorthand for GetOrDefault with a time.Time destination.
+func (p *Parser) GetTime(ctx context.Context, envVar string, defaultVal time.Time, opts ...EnvParseOption) (time.Time, error) {
+	return GetOrDefault(ctx, p, envVar, defaultVal, opts...)
+}
+
+// GetStrings is a non-generic shorthand for GetOrDefault with a []string destination.
+func (p *Parser) GetStrings(ctx context.Context, envVar string, defaultVal []string, opts ...EnvParseOption) ([]string, error) {
+	return GetOrDefault(ctx, p, envVar, defaultVal, opts...)
+}
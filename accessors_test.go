@@ -0,0 +1,51 @@
+package env_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ndisidore/go-env"
+)
+
+func TestParserAccessors(t *testing.T) {
+	t.Parallel()
+
+	loader := func(key string) string {
+		return map[string]string{"STR": "a string", "BOOL": "true", "INT": "-3", "DUR": "5s", "LIST": "a,b"}[key]
+	}
+	p, err := env.NewBuilder().With(env.WithEnvLoader(loader)).Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx := context.Background()
+
+	if ret, err := p.GetString(ctx, "STR", ""); err != nil || ret != "a string" {
+		t.Logf("GetString returned (%s, %v)", ret, err)
+		t.Fail()
+	}
+	if ret, err := p.GetBool(ctx, "BOOL", false); err != nil || !ret {
+		t.Logf("GetBool returned (%t, %v)", ret, err)
+		t.Fail()
+	}
+	if ret, err := p.GetInt(ctx, "INT", 0); err != nil || ret != -3 {
+		t.Logf("GetInt returned (%d, %v)", ret, err)
+		t.Fail()
+	}
+	if ret, err := p.GetDuration(ctx, "DUR", 0); err != nil || ret != 5*time.Second {
+		t.Logf("GetDuration returned (%s, %v)", ret, err)
+		t.Fail()
+	}
+	if ret, err := p.GetStrings(ctx, "LIST", nil); err != nil || len(ret) != 2 {
+		t.Logf("GetStrings returned (%v, %v)", ret, err)
+		t.Fail()
+	}
+	if ret, err := p.GetFloat64(ctx, "UNKNOWN_ENV", 1.5); err != nil || ret != 1.5 {
+		t.Logf("GetFloat64 returned (%f, %v)", ret, err)
+		t.Fail()
+	}
+	if _, err := p.GetInt(ctx, "STR", 0); err == nil {
+		t.Log("expected GetInt to fail on a non-numeric value")
+		t.Fail()
+	}
+}
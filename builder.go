@@ -0,0 +1,145 @@
+package env
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+type (
+	// Parser is a read-only, pre-configured parser. Its options are fixed at build time, so a single Parser is safe for concurrent use.
+	Parser struct {
+		opts envParseOpts
+	}
+
+	// Builder accumulates options for a Parser. Unlike a Parser, a Builder is mutable and not safe for concurrent use.
+	Builder struct {
+		opts envParseOpts
+		err  error
+	}
+)
+
+// NewBuilder returns a Builder seeded with the package default options.
+func NewBuilder() *Builder {
+	return &Builder{opts: loadDefaultParseOptions()}
+}
+
+// With applies the provided options to the builder. The first option error is retained and surfaced by Build.
+func (b *Builder) With(opts ...EnvParseOption) *Builder {
+	for _, opt := range opts {
+		if b.err != nil {
+			break
+		}
+		if err := opt(&b.opts); err != nil {
+			b.err = fmt.Errorf("option error: %w", err)
+		}
+	}
+
+	return b
+}
+
+// Build returns an immutable Parser from the options accumulated so far.
+func (b *Builder) Build() (*Parser, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	p := &Parser{opts: b.opts}
+	p.opts.keys = new(keyIndex)
+	return p, nil
+}
+
+// NewParser is shorthand for NewBuilder().With(opts...).Build().
+func NewParser(opts ...EnvParseOption) (*Parser, error) {
+	return NewBuilder().With(opts...).Build()
+}
+
+// Builder returns a new Builder seeded with this parser's options, allowing a derived parser to be configured without affecting the original.
+func (p *Parser) Builder() *Builder {
+	return &Builder{opts: p.opts}
+}
+
+// WithOverlay returns a derived parser whose lookups consult the overrides first and fall back to this parser's loader.
+// The overrides are copied, so later changes to the map have no effect. Neither the parser nor the process environment is modified.
+func (p *Parser) WithOverlay(overrides map[string]string) *Parser {
+	overlay := make(map[string]string, len(overrides))
+	for k, v := range overrides {
+		overlay[k] = v
+	}
+
+	derived := &Parser{opts: p.opts}
+	base := p.opts.envLoader
+	derived.opts.envLoader = func(key string) string {
+		if v, ok := overlay[key]; ok {
+			return v
+		}
+		return base(key)
+	}
+	baseLookuper := p.opts.envLookuper
+	derived.opts.envLookuper = func(key string) (string, bool) {
+		if v, ok := overlay[key]; ok {
+			return v, true
+		}
+		if baseLookuper == nil {
+			return "", false
+		}
+		return baseLookuper(key)
+	}
+	if baseLister := p.opts.keyLister; baseLister != nil {
+		derived.opts.keyLister = func() []string {
+			keys := baseLister()
+			for k := range overlay {
+				keys = append(keys, k)
+			}
+			return keys
+		}
+	}
+	derived.opts.keys = new(keyIndex)
+
+	return derived
+}
+
+// MustGetOrDefault is the Parser counterpart to MustFromEnvOrDefault.
+func MustGetOrDefault[T any](ctx context.Context, p *Parser, envVar string, defaultVal T, opts ...EnvParseOption) (dest T) {
+	parseOpts := p.opts
+	for _, opt := range opts {
+		if err := opt(&parseOpts); err != nil {
+			p.opts.fatal(ctx, envVar, fmt.Errorf("option error: %w", err))
+		}
+	}
+
+	parsed, err := parse(ctx, &parseOpts, envVar, defaultVal)
+	if err != nil {
+		parseOpts.fatal(ctx, envVar, err)
+	}
+	return parsed
+}
+
+// GetOrDefault is the Parser counterpart to FromEnvOrDefault. Any options provided apply to this call only and never modify the parser.
+func GetOrDefault[T any](ctx context.Context, p *Parser, envVar string, defaultVal T, opts ...EnvParseOption) (dest T, err error) {
+	parseOpts := p.opts
+	for _, opt := range opts {
+		if err := opt(&parseOpts); err != nil {
+			return dest, fmt.Errorf("option error: %w", err)
+		}
+	}
+
+	return parse(ctx, &parseOpts, envVar, defaultVal)
+}
+
+// GetOrNil is the Parser counterpart to FromEnvOrNil. Any options provided apply to this call only and never modify the parser.
+func GetOrNil[T any](ctx context.Context, p *Parser, envVar string, opts ...EnvParseOption) (*T, error) {
+	parseOpts := p.opts
+	for _, opt := range opts {
+		if err := opt(&parseOpts); err != nil {
+			return nil, fmt.Errorf("option error: %w", err)
+		}
+	}
+
+	return parseOrNil[T](ctx, &parseOpts, envVar)
+}
+
+// Get parses the env var using the parser, returning a *MissingError if it is unset. It is GetOrDefault combined with WithRequired.
+func Get[T any](ctx context.Context, p *Parser, envVar string, opts ...EnvParseOption) (dest T, err error) {
+	return GetOrDefault(ctx, p, envVar, dest, append(slices.Clip(opts), WithRequired())...)
+}
@@ -0,0 +1,132 @@
+package env_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ndisidore/go-env"
+)
+
+func TestParserBuilder(t *testing.T) {
+	t.Parallel()
+
+	loader := func(key string) string {
+		return map[string]string{"LIST": "a;b", "WHEN": "Fri, 01 Jan 2021 00:00:00 UTC"}[key]
+	}
+
+	t.Run("options are applied", func(t *testing.T) {
+		t.Parallel()
+		p, err := env.NewBuilder().With(env.WithEnvLoader(loader), env.WithEnvParseSeparator(";")).Build()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		ret, err := env.GetOrDefault(context.Background(), p, "LIST", []string{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(ret) != 2 || ret[0] != "a" || ret[1] != "b" {
+			t.Logf("return value (%v) does not match expected ([a b])", ret)
+			t.Fail()
+		}
+	})
+
+	t.Run("NewParser and Get", func(t *testing.T) {
+		t.Parallel()
+		p, err := env.NewParser(env.WithEnvLoader(loader), env.WithEnvParseSeparator(";"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		ret, err := env.Get[[]string](context.Background(), p, "LIST")
+		if err != nil || len(ret) != 2 {
+			t.Logf("Get returned (%v, %v)", ret, err)
+			t.Fail()
+		}
+		var me *env.MissingError
+		if _, err := env.Get[int](context.Background(), p, "UNSET"); !errors.As(err, &me) {
+			t.Logf("unexpected error: %v", err)
+			t.Fail()
+		}
+	})
+
+	t.Run("option errors surface on build", func(t *testing.T) {
+		t.Parallel()
+		_, err := env.NewBuilder().With(env.WithEnvParseSeparator("")).Build()
+		if err == nil || !strings.Contains(err.Error(), "separator cannot be empty") {
+			t.Logf("unexpected error: %v", err)
+			t.Fail()
+		}
+	})
+
+	t.Run("per-call options do not modify the parser", func(t *testing.T) {
+		t.Parallel()
+		p, err := env.NewBuilder().With(env.WithEnvLoader(loader)).Build()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := env.GetOrDefault(context.Background(), p, "WHEN", time.Time{}, env.WithTimeLayout(time.RFC1123)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := env.GetOrDefault(context.Background(), p, "WHEN", time.Time{}); err == nil {
+			t.Log("expected RFC3339 layout to be retained by the parser")
+			t.Fail()
+		}
+	})
+
+	t.Run("derived builder does not modify the parser", func(t *testing.T) {
+		t.Parallel()
+		p, err := env.NewBuilder().With(env.WithEnvLoader(loader)).Build()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		derived, err := p.Builder().With(env.WithEnvParseSeparator(";")).Build()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if ret, _ := env.GetOrDefault(context.Background(), derived, "LIST", []string{}); len(ret) != 2 {
+			t.Logf("derived return value (%v) does not have 2 items", ret)
+			t.Fail()
+		}
+		if ret, _ := env.GetOrDefault(context.Background(), p, "LIST", []string{}); len(ret) != 1 {
+			t.Logf("original return value (%v) does not have 1 item", ret)
+			t.Fail()
+		}
+	})
+}
+
+func TestParserWithOverlay(t *testing.T) {
+	t.Parallel()
+
+	loader := func(key string) string {
+		return map[string]string{"PORT": "8080", "HOST": "localhost"}[key]
+	}
+	base, err := env.NewBuilder().With(env.WithEnvLoader(loader)).Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	overrides := map[string]string{"PORT": "9090"}
+	overlay := base.WithOverlay(overrides)
+	overrides["HOST"] = "mutated"
+
+	cases := []struct {
+		p        *env.Parser
+		key      string
+		expected string
+	}{
+		{p: overlay, key: "PORT", expected: "9090"},
+		{p: overlay, key: "HOST", expected: "localhost"},
+		{p: base, key: "PORT", expected: "8080"},
+	}
+	for _, tt := range cases {
+		ret, err := env.GetOrDefault(context.Background(), tt.p, tt.key, "")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if ret != tt.expected {
+			t.Logf("return value (%s) does not match expected (%s)", ret, tt.expected)
+			t.Fail()
+		}
+	}
+}
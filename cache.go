@@ -0,0 +1,51 @@
+package env
+
+import (
+	"sync"
+	"time"
+)
+
+// cacheEntry is a cached value, whose ready channel is closed once the lookup filling it completes.
+type cacheEntry struct {
+	val     string
+	expires time.Time
+	ready   chan struct{}
+}
+
+// CachedLoader returns a loader that memoizes the values inner returns for each key for ttl, so remote loaders are not consulted on every parse.
+// Concurrent lookups of a key that is not cached share a single call to inner. A non-positive ttl caches values for the life of the loader.
+//
+// Empty values are cached like any other, so an unset key is not looked up again until it expires either. Expiry is measured by the system clock unless
+// another is provided via WithLoaderClock.
+func CachedLoader(inner EnvLoader, ttl time.Duration, opts ...LoaderOption) EnvLoader {
+	clock := newLoaderOpts(opts).clock
+	var (
+		mu      sync.Mutex
+		entries = make(map[string]*cacheEntry)
+	)
+	return func(key string) string {
+		mu.Lock()
+		if e, ok := entries[key]; ok {
+			select {
+			case <-e.ready:
+				if ttl <= 0 || clock.Now().Before(e.expires) {
+					mu.Unlock()
+					return e.val
+				}
+			default:
+				// another lookup is filling the entry
+				mu.Unlock()
+				<-e.ready
+				return e.val
+			}
+		}
+		e := &cacheEntry{ready: make(chan struct{})}
+		entries[key] = e
+		mu.Unlock()
+
+		defer close(e.ready)
+		e.val = inner(key)
+		e.expires = clock.Now().Add(ttl)
+		return e.val
+	}
+}
@@ -0,0 +1,75 @@
+package env_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ndisidore/go-env"
+)
+
+func TestCachedLoader(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	inner := func(key string) string {
+		calls.Add(1)
+		<-release
+		return map[string]string{"PORT": "8080"}[key]
+	}
+	clock := newFakeClock(time.Date(2026, time.October, 15, 0, 0, 0, 0, time.UTC))
+	loader := env.CachedLoader(inner, time.Minute, env.WithLoaderClock(clock))
+
+	// concurrent lookups share a single call to the inner loader
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if port, err := env.FromEnvOrDefault(context.Background(), "PORT", 0, env.WithEnvLoader(loader)); err != nil || port != 8080 {
+				t.Logf("FromEnvOrDefault returned (%d, %v)", port, err)
+				t.Fail()
+			}
+		}()
+	}
+	for calls.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+	if n := calls.Load(); n != 1 {
+		t.Logf("inner loader called %d times, want 1", n)
+		t.Fail()
+	}
+
+	loader("PORT")
+	if n := calls.Load(); n != 1 {
+		t.Logf("cached value was looked up again (%d calls)", n)
+		t.Fail()
+	}
+	clock.Advance(59 * time.Second)
+	if loader("PORT") != "8080" || calls.Load() != 1 {
+		t.Logf("value expired before its ttl (%d calls)", calls.Load())
+		t.Fail()
+	}
+	clock.Advance(time.Second)
+	if loader("PORT") != "8080" || calls.Load() != 2 {
+		t.Logf("expired value was not looked up again (%d calls)", calls.Load())
+		t.Fail()
+	}
+
+	loader("UNSET")
+	loader("UNSET")
+	if n := calls.Load(); n != 3 {
+		t.Logf("unset value was not cached (%d calls)", n)
+		t.Fail()
+	}
+
+	if loader("PORT") != "8080" || calls.Load() != 3 {
+		t.Logf("refreshed value was looked up again (%d calls)", calls.Load())
+		t.Fail()
+	}
+}
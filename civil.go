@@ -0,0 +1,94 @@
+package env
+
+import (
+	"cmp"
+	"fmt"
+	"time"
+)
+
+type (
+	// Date is a calendar date without a time or zone, parsed from `2006-01-02`, e.g. for cutover or expiry dates.
+	Date struct {
+		Year  int
+		Month time.Month
+		Day   int
+	}
+
+	// TimeOfDay is a wall clock time without a date or zone, parsed from `15:04` or `15:04:05`, e.g. for a daily job's start time.
+	TimeOfDay struct {
+		Hour   int
+		Minute int
+		Second int
+	}
+)
+
+// ParseDate parses a date in the form `2006-01-02`.
+func ParseDate(in string) (Date, error) {
+	t, err := time.Parse(time.DateOnly, in)
+	if err != nil {
+		return Date{}, fmt.Errorf("invalid date %q (want e.g. 2024-06-01)", in)
+	}
+	return DateOf(t), nil
+}
+
+// DateOf returns the date of t in its zone.
+func DateOf(t time.Time) Date {
+	y, m, d := t.Date()
+	return Date{Year: y, Month: m, Day: d}
+}
+
+// In returns the time at the start of the date in loc.
+func (d Date) In(loc *time.Location) time.Time {
+	return time.Date(d.Year, d.Month, d.Day, 0, 0, 0, 0, loc)
+}
+
+// Compare returns -1, 0 or 1 as d is before, the same as, or after other.
+func (d Date) Compare(other Date) int {
+	return cmp.Or(cmp.Compare(d.Year, other.Year), cmp.Compare(d.Month, other.Month), cmp.Compare(d.Day, other.Day))
+}
+
+// String returns the date in the form it is parsed from.
+func (d Date) String() string {
+	return fmt.Sprintf("%04d-%02d-%02d", d.Year, d.Month, d.Day)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (d *Date) UnmarshalText(text []byte) (err error) {
+	*d, err = ParseDate(string(text))
+	return err
+}
+
+// ParseTimeOfDay parses a time of day in the form `15:04` or `15:04:05`.
+func ParseTimeOfDay(in string) (TimeOfDay, error) {
+	offset, err := parseTimeOfDay(in, false)
+	if err != nil {
+		return TimeOfDay{}, err
+	}
+	return TimeOfDay{Hour: int(offset / time.Hour), Minute: int(offset % time.Hour / time.Minute), Second: int(offset % time.Minute / time.Second)}, nil
+}
+
+// On returns the time of day on date d in loc.
+func (t TimeOfDay) On(d Date, loc *time.Location) time.Time {
+	return time.Date(d.Year, d.Month, d.Day, t.Hour, t.Minute, t.Second, 0, loc)
+}
+
+// Compare returns -1, 0 or 1 as t is before, the same as, or after other.
+func (t TimeOfDay) Compare(other TimeOfDay) int {
+	return cmp.Compare(t.offset(), other.offset())
+}
+
+// String returns the time of day in the form it is parsed from, adding seconds only when there are any.
+func (t TimeOfDay) String() string {
+	return formatTimeOfDay(t.offset())
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (t *TimeOfDay) UnmarshalText(text []byte) (err error) {
+	*t, err = ParseTimeOfDay(string(text))
+	return err
+}
+
+// offset returns the time of day as an offset from midnight.
+func (t TimeOfDay) offset() time.Duration {
+	return time.Duration(t.Hour)*time.Hour + time.Duration(t.Minute)*time.Minute + time.Duration(t.Second)*time.Second
+}
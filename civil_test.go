@@ -0,0 +1,107 @@
+package env_test
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ndisidore/go-env"
+)
+
+func TestCivil(t *testing.T) {
+	t.Parallel()
+
+	loader := func(key string) string {
+		return map[string]string{
+			"CUTOVER":  "2024-06-01",
+			"HOLIDAYS": "2024-12-25,2025-01-01",
+			"BAD_DATE": "2024-02-30",
+			"START_AT": "14:30",
+			"RUN_AT":   "06:00,18:00:30",
+			"BAD_TIME": "2pm",
+		}[key]
+	}
+	ctx := context.Background()
+
+	if ret, err := env.FromEnvOrDefault(ctx, "CUTOVER", env.Date{}, env.WithEnvLoader(loader)); err != nil || ret != (env.Date{Year: 2024, Month: time.June, Day: 1}) {
+		t.Logf("FromEnvOrDefault returned (%v, %v)", ret, err)
+		t.Fail()
+	}
+	if ret, err := env.FromEnvOrDefault(ctx, "HOLIDAYS", []env.Date{}, env.WithEnvLoader(loader)); err != nil ||
+		!reflect.DeepEqual(ret, []env.Date{{Year: 2024, Month: time.December, Day: 25}, {Year: 2025, Month: time.January, Day: 1}}) {
+		t.Logf("FromEnvOrDefault returned (%v, %v)", ret, err)
+		t.Fail()
+	}
+	if ret, err := env.FromEnvOrDefault(ctx, "START_AT", env.TimeOfDay{}, env.WithEnvLoader(loader)); err != nil || ret.String() != "14:30" {
+		t.Logf("FromEnvOrDefault returned (%v, %v)", ret, err)
+		t.Fail()
+	}
+	if ret, err := env.FromEnvOrDefault(ctx, "RUN_AT", []env.TimeOfDay{}, env.WithEnvLoader(loader)); err != nil ||
+		!reflect.DeepEqual(ret, []env.TimeOfDay{{Hour: 6}, {Hour: 18, Second: 30}}) {
+		t.Logf("FromEnvOrDefault returned (%v, %v)", ret, err)
+		t.Fail()
+	}
+	for key, dest := range map[string]any{"BAD_DATE": env.Date{}, "BAD_TIME": env.TimeOfDay{}} {
+		var err error
+		switch d := dest.(type) {
+		case env.Date:
+			_, err = env.FromEnvOrDefault(ctx, key, d, env.WithEnvLoader(loader))
+		case env.TimeOfDay:
+			_, err = env.FromEnvOrDefault(ctx, key, d, env.WithEnvLoader(loader))
+		}
+		if err == nil || !strings.Contains(err.Error(), "invalid") {
+			t.Logf("unexpected error for %s: %v", key, err)
+			t.Fail()
+		}
+	}
+
+	start := env.TimeOfDay{Hour: 14, Minute: 30}
+	if at := start.On(env.Date{Year: 2024, Month: time.June, Day: 1}, time.UTC); !at.Equal(time.Date(2024, time.June, 1, 14, 30, 0, 0, time.UTC)) {
+		t.Logf("unexpected time: %v", at)
+		t.Fail()
+	}
+	if d := env.DateOf(time.Date(2024, time.June, 1, 23, 0, 0, 0, time.UTC)); d.String() != "2024-06-01" || !d.In(time.UTC).Equal(time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Logf("unexpected date: %v", d)
+		t.Fail()
+	}
+}
+
+func TestWithRange(t *testing.T) {
+	t.Parallel()
+
+	loader := func(key string) string {
+		return map[string]string{"CUTOVER": "2024-06-01", "RUN_AT": "06:00,23:30"}[key]
+	}
+	ctx := context.Background()
+	business := env.WithRange(env.TimeOfDay{Hour: 5}, env.TimeOfDay{Hour: 22})
+
+	if _, err := env.FromEnvOrDefault(ctx, "CUTOVER", env.Date{}, env.WithEnvLoader(loader),
+		env.WithRange(env.Date{Year: 2025, Month: time.January, Day: 1}, env.Date{Year: 2025, Month: time.December, Day: 31})); err == nil ||
+		!strings.Contains(err.Error(), "value 2024-06-01 is outside the range 2025-01-01 to 2025-12-31") {
+		t.Logf("unexpected error: %v", err)
+		t.Fail()
+	}
+	if _, err := env.FromEnvOrDefault(ctx, "RUN_AT", []env.TimeOfDay{}, env.WithEnvLoader(loader), business); err == nil || !strings.Contains(err.Error(), "item (pos: 1): value 23:30") {
+		t.Logf("unexpected error: %v", err)
+		t.Fail()
+	}
+
+	var warnings []env.Warning
+	ret, err := env.FromEnvOrDefault(ctx, "RUN_AT", []env.TimeOfDay{}, env.WithEnvLoader(loader), business, env.WithClamping(),
+		env.WithWarningHandler(func(w env.Warning) { warnings = append(warnings, w) }))
+	if err != nil || !reflect.DeepEqual(ret, []env.TimeOfDay{{Hour: 6}, {Hour: 22}}) {
+		t.Logf("FromEnvOrDefault returned (%v, %v)", ret, err)
+		t.Fail()
+	}
+	if len(warnings) != 1 || warnings[0].Kind != env.WarnClamped {
+		t.Logf("unexpected warnings: %v", warnings)
+		t.Fail()
+	}
+
+	if _, err := env.FromEnvOrDefault(ctx, "CUTOVER", env.Date{}, env.WithRange(env.Date{Year: 2025}, env.Date{Year: 2024})); err == nil {
+		t.Log("expected an error for an inverted range")
+		t.Fail()
+	}
+}
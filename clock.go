@@ -0,0 +1,80 @@
+package env
+
+import (
+	"errors"
+	"time"
+)
+
+type (
+	// Clock abstracts time for time-relative features such as deprecation sunsets and polling, allowing tests to drive them deterministically.
+	Clock interface {
+		Now() time.Time
+		NewTicker(d time.Duration) Ticker
+	}
+
+	// Ticker mirrors the parts of time.Ticker used by the parser.
+	Ticker interface {
+		C() <-chan time.Time
+		Stop()
+	}
+
+	// LoaderOption customizes loaders and middlewares that measure time, such as CachedLoader.
+	LoaderOption func(o *loaderOpts)
+
+	loaderOpts struct {
+		clock Clock
+	}
+
+	realClock struct{}
+
+	realTicker struct {
+		*time.Ticker
+	}
+)
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) NewTicker(d time.Duration) Ticker {
+	return realTicker{time.NewTicker(d)}
+}
+
+func (t realTicker) C() <-chan time.Time {
+	return t.Ticker.C
+}
+
+// WithClock allows overriding the clock used by time-relative features. Primarily used for testing.
+func WithClock(clock Clock) EnvParseOption {
+	return func(o *envParseOpts) error {
+		if clock == nil {
+			return errors.New("clock cannot be nil")
+		}
+
+		o.clock = clock
+		return nil
+	}
+}
+
+// SystemClock returns the Clock backed by the time package, which is used unless another is provided.
+func SystemClock() Clock {
+	return realClock{}
+}
+
+// WithLoaderClock overrides the clock used by a loader or middleware, e.g. for cache expiry or lookup timings. A nil clock uses the system clock.
+func WithLoaderClock(clock Clock) LoaderOption {
+	return func(o *loaderOpts) {
+		if clock != nil {
+			o.clock = clock
+		}
+	}
+}
+
+// newLoaderOpts applies opts over the defaults.
+func newLoaderOpts(opts []LoaderOption) loaderOpts {
+	o := loaderOpts{clock: realClock{}}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
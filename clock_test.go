@@ -0,0 +1,82 @@
+package env_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ndisidore/go-env"
+)
+
+type (
+	fakeClock struct {
+		mu     sync.Mutex
+		now    time.Time
+		ticker *fakeTicker
+	}
+
+	fakeTicker struct {
+		ch chan time.Time
+	}
+)
+
+func newFakeClock(now time.Time) *fakeClock {
+	return &fakeClock{now: now, ticker: &fakeTicker{ch: make(chan time.Time)}}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) NewTicker(time.Duration) env.Ticker {
+	return c.ticker
+}
+
+// Advance moves the clock forward without ticking.
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// Tick advances the clock and blocks until the tick is received.
+func (c *fakeClock) Tick(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	now := c.now
+	c.mu.Unlock()
+	c.ticker.ch <- now
+}
+
+func (t *fakeTicker) C() <-chan time.Time {
+	return t.ch
+}
+
+func (t *fakeTicker) Stop() {}
+
+func TestWithClock(t *testing.T) {
+	t.Parallel()
+
+	sunset := time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC)
+	loader := func(key string) string {
+		return map[string]string{"OLD": "1"}[key]
+	}
+	cases := []struct {
+		now         time.Time
+		expectedErr bool
+	}{
+		{now: sunset.Add(-time.Hour)},
+		{now: sunset.Add(time.Hour), expectedErr: true},
+	}
+	for _, tt := range cases {
+		_, err := env.FromEnvOrDefault(context.Background(), "NEW", 0, env.WithEnvLoader(loader), env.WithClock(newFakeClock(tt.now)),
+			env.WithDeprecatedKey("OLD", env.RemovedAfter(sunset)))
+		if (err != nil) != tt.expectedErr {
+			t.Logf("unexpected error at %s: %v", tt.now, err)
+			t.Fail()
+		}
+	}
+}
@@ -0,0 +1,142 @@
+// Command envexport resolves env vars through a loader spec and prints them as shell statements, so a service's environment can be replicated locally.
+//
+// Usage:
+//
+//	envexport [-spec SPEC] [-shell bash|fish|powershell] [-mask PATTERNS] KEY...
+//
+// For example, `eval "$(envexport -spec 'chain(env,dotenv(.env))' DATABASE_URL PORT)"`. Unset keys are skipped.
+// Keys matching any of the comma separated glob patterns passed to -mask are printed with a redacted value.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/ndisidore/go-env"
+)
+
+func main() {
+	os.Exit(run(context.Background(), os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes the command, returning the exit code.
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("envexport", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	spec := fs.String("spec", "env", "loader spec to resolve keys through, see env.LoaderFromSpec")
+	shell := fs.String("shell", "bash", "syntax to print: bash, fish or powershell")
+	mask := fs.String("mask", "", "comma separated glob patterns of keys whose values are redacted")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fmt.Fprintln(stderr, "envexport: at least one key is required")
+		return 2
+	}
+
+	format, ok := formats[*shell]
+	if !ok {
+		fmt.Fprintf(stderr, "envexport: unknown shell %q (want bash, fish or powershell)\n", *shell)
+		return 2
+	}
+	var masked []string
+	if *mask != "" {
+		masked = strings.Split(*mask, ",")
+	}
+	for _, pattern := range masked {
+		if _, err := path.Match(pattern, ""); err != nil {
+			fmt.Fprintf(stderr, "envexport: invalid mask pattern %q: %v\n", pattern, err)
+			return 2
+		}
+	}
+
+	loader, err := env.LoaderFromSpec(*spec)
+	if err != nil {
+		fmt.Fprintf(stderr, "envexport: %v\n", err)
+		return 1
+	}
+	p, err := env.NewParser(env.WithEnvLoader(loader))
+	if err != nil {
+		fmt.Fprintf(stderr, "envexport: %v\n", err)
+		return 1
+	}
+
+	for _, key := range fs.Args() {
+		if !validName(key) {
+			fmt.Fprintf(stderr, "envexport: %q is not a valid variable name\n", key)
+			return 1
+		}
+		val, err := env.GetOrNil[string](ctx, p, key)
+		if err != nil {
+			fmt.Fprintf(stderr, "envexport: %v\n", err)
+			return 1
+		}
+		if val == nil {
+			continue
+		}
+		if matchesAny(masked, key) {
+			*val = "<redacted>"
+		}
+		fmt.Fprintln(stdout, format(key, *val))
+	}
+	return 0
+}
+
+// formats renders an assignment of a quoted value for each supported shell.
+var formats = map[string]func(key, val string) string{
+	"bash": func(key, val string) string {
+		return "export " + key + "=" + quotePOSIX(val)
+	},
+	"fish": func(key, val string) string {
+		return "set -x " + key + " " + quoteFish(val)
+	},
+	"powershell": func(key, val string) string {
+		return "$env:" + key + " = " + quotePowerShell(val)
+	},
+}
+
+// quotePOSIX single-quotes val, within which nothing is special except the quote itself, which is closed, escaped and reopened.
+func quotePOSIX(val string) string {
+	return "'" + strings.ReplaceAll(val, "'", `'\''`) + "'"
+}
+
+// quoteFish single-quotes val. Unlike POSIX shells, fish treats backslash as an escape within single quotes, for itself and the quote.
+func quoteFish(val string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(val) + "'"
+}
+
+// quotePowerShell single-quotes val, within which a quote is escaped by doubling it. PowerShell also accepts typographic quotes as delimiters, so those are doubled too.
+func quotePowerShell(val string) string {
+	return "'" + strings.NewReplacer("'", "''", "‘", "‘‘", "’", "’’", "‚", "‚‚", "‛", "‛‛").Replace(val) + "'"
+}
+
+// validName reports whether key can be assigned in every supported shell without further quoting.
+func validName(key string) bool {
+	if key == "" {
+		return false
+	}
+	for i, r := range key {
+		switch {
+		case r >= 'A' && r <= 'Z', r >= 'a' && r <= 'z', r == '_':
+		case r >= '0' && r <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// matchesAny reports whether key matches any of the glob patterns.
+func matchesAny(patterns []string, key string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, key); ok {
+			return true
+		}
+	}
+	return false
+}
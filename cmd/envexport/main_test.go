@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	dotenv := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(dotenv, []byte("GREETING=\"it's a \\\\ test\"\nAPI_TOKEN=hunter2\nPORT=8080\n"), 0o600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	spec := "dotenv(" + dotenv + ")"
+
+	cases := []struct {
+		shell    string
+		expected string
+	}{
+		{shell: "bash", expected: "export GREETING='it'\\''s a \\ test'\nexport API_TOKEN='<redacted>'\nexport PORT='8080'\n"},
+		{shell: "fish", expected: "set -x GREETING 'it\\'s a \\\\ test'\nset -x API_TOKEN '<redacted>'\nset -x PORT '8080'\n"},
+		{shell: "powershell", expected: "$env:GREETING = 'it''s a \\ test'\n$env:API_TOKEN = '<redacted>'\n$env:PORT = '8080'\n"},
+	}
+	for _, tt := range cases {
+		t.Run(tt.shell, func(t *testing.T) {
+			t.Parallel()
+			var stdout, stderr bytes.Buffer
+			code := run(context.Background(), []string{"-spec", spec, "-shell", tt.shell, "-mask", "*_TOKEN", "GREETING", "API_TOKEN", "UNSET", "PORT"}, &stdout, &stderr)
+			if code != 0 {
+				t.Fatalf("unexpected exit code %d: %s", code, stderr.String())
+			}
+			if stdout.String() != tt.expected {
+				t.Logf("output (%q) does not match expected (%q)", stdout.String(), tt.expected)
+				t.Fail()
+			}
+		})
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name                string
+		args                []string
+		expectedCode        int
+		expectedErrContains string
+	}{
+		{name: "no keys", args: []string{}, expectedCode: 2, expectedErrContains: "at least one key"},
+		{name: "unknown shell", args: []string{"-shell", "tcsh", "PORT"}, expectedCode: 2, expectedErrContains: `unknown shell "tcsh"`},
+		{name: "bad mask", args: []string{"-mask", "[", "PORT"}, expectedCode: 2, expectedErrContains: "invalid mask pattern"},
+		{name: "bad spec", args: []string{"-spec", "nope", "PORT"}, expectedCode: 1, expectedErrContains: `unknown loader scheme "nope"`},
+		{name: "bad key", args: []string{"PORT;rm"}, expectedCode: 1, expectedErrContains: "not a valid variable name"},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var stdout, stderr bytes.Buffer
+			if code := run(context.Background(), tt.args, &stdout, &stderr); code != tt.expectedCode {
+				t.Logf("exit code (%d) does not match expected (%d)", code, tt.expectedCode)
+				t.Fail()
+			}
+			if !strings.Contains(stderr.String(), tt.expectedErrContains) {
+				t.Logf("unexpected stderr: %s", stderr.String())
+				t.Fail()
+			}
+		})
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !unix && !tinygo
+
+package main
+
+import "os/exec"
+
+// exitCode returns the exit code of the command.
+func exitCode(err *exec.ExitError) int {
+	return err.ExitCode()
+}
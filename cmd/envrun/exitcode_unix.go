@@ -0,0 +1,16 @@
+//go:build unix && !tinygo
+
+package main
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// exitCode returns the exit code of the command, or 128 plus the signal number if it was killed by a signal.
+func exitCode(err *exec.ExitError) int {
+	if ws, ok := err.Sys().(syscall.WaitStatus); ok && ws.Signaled() {
+		return 128 + int(ws.Signal())
+	}
+	return err.ExitCode()
+}
@@ -0,0 +1,81 @@
+//go:build !tinygo
+
+// Command envrun resolves env vars through a loader spec and runs a command with them added to its environment.
+//
+// Usage:
+//
+//	envrun [-spec SPEC] -keys KEYS -- COMMAND [ARG...]
+//
+// For example, `envrun -spec 'chain(env,dotenv(.env))' -keys DATABASE_URL,PORT -- ./server`. The command inherits the current environment,
+// overlaid with whichever of the comma separated keys are set, and its exit code is passed through. As in shells, a command killed by a signal exits with
+// 128 plus the signal number.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/ndisidore/go-env"
+)
+
+func main() {
+	os.Exit(run(context.Background(), os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes the command, returning the exit code.
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("envrun", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	spec := fs.String("spec", "env", "loader spec to resolve keys through, see env.LoaderFromSpec")
+	keys := fs.String("keys", "", "comma separated keys to resolve and pass to the command")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	var keyList []string
+	for _, key := range strings.Split(*keys, ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			keyList = append(keyList, key)
+		}
+	}
+	if len(keyList) == 0 {
+		fmt.Fprintln(stderr, "envrun: -keys is required")
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fmt.Fprintln(stderr, "envrun: a command is required")
+		return 2
+	}
+
+	loader, err := env.LoaderFromSpec(*spec)
+	if err != nil {
+		fmt.Fprintf(stderr, "envrun: %v\n", err)
+		return 1
+	}
+	p, err := env.NewParser(env.WithEnvLoader(loader))
+	if err != nil {
+		fmt.Fprintf(stderr, "envrun: %v\n", err)
+		return 1
+	}
+	cmd, err := p.Command(ctx, keyList, fs.Arg(0), fs.Args()[1:]...)
+	if err != nil {
+		fmt.Fprintf(stderr, "envrun: %v\n", err)
+		return 1
+	}
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = stdin, stdout, stderr
+
+	var exitErr *exec.ExitError
+	switch err := cmd.Run(); {
+	case errors.As(err, &exitErr):
+		return exitCode(exitErr)
+	case err != nil:
+		fmt.Fprintf(stderr, "envrun: %v\n", err)
+		return 1
+	}
+	return 0
+}
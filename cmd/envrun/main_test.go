@@ -0,0 +1,81 @@
+//go:build !tinygo
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not available")
+	}
+	dotenv := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(dotenv, []byte("GREETING=hello\n"), 0o600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	spec := "dotenv(" + dotenv + ")"
+
+	var stdout, stderr bytes.Buffer
+	code := run(context.Background(), []string{"-spec", spec, "-keys", "GREETING,UNSET", "--", "sh", "-c", `echo "$GREETING"; exit 3`}, nil, &stdout, &stderr)
+	if code != 3 {
+		t.Logf("exit code (%d) does not match the command's (3): %s", code, stderr.String())
+		t.Fail()
+	}
+	if stdout.String() != "hello\n" {
+		t.Logf("unexpected output: %q", stdout.String())
+		t.Fail()
+	}
+
+	stdout.Reset()
+	code = run(context.Background(), []string{"-spec", spec, "-keys", " GREETING , ,", "--", "sh", "-c", `echo "$GREETING"`}, nil, &stdout, &stderr)
+	if code != 0 || stdout.String() != "hello\n" {
+		t.Logf("expected keys to be trimmed, got exit code %d and output %q: %s", code, stdout.String(), stderr.String())
+		t.Fail()
+	}
+
+	// shells report a command killed by a signal as 128 plus the signal number, here SIGKILL
+	if code := run(context.Background(), []string{"-keys", "PORT", "--", "sh", "-c", "kill -9 $$"}, nil, &stdout, &stderr); code != 137 {
+		t.Logf("exit code (%d) does not match expected (137)", code)
+		t.Fail()
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name                string
+		args                []string
+		expectedCode        int
+		expectedErrContains string
+	}{
+		{name: "no keys", args: []string{"--", "true"}, expectedCode: 2, expectedErrContains: "-keys is required"},
+		{name: "blank keys", args: []string{"-keys", " , ", "--", "true"}, expectedCode: 2, expectedErrContains: "-keys is required"},
+		{name: "no command", args: []string{"-keys", "PORT"}, expectedCode: 2, expectedErrContains: "a command is required"},
+		{name: "bad spec", args: []string{"-spec", "nope", "-keys", "PORT", "--", "true"}, expectedCode: 1, expectedErrContains: `unknown loader scheme "nope"`},
+		{name: "missing command", args: []string{"-keys", "PORT", "--", "envrun-no-such-command"}, expectedCode: 1, expectedErrContains: "executable file not found"},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var stdout, stderr bytes.Buffer
+			if code := run(context.Background(), tt.args, nil, &stdout, &stderr); code != tt.expectedCode {
+				t.Logf("exit code (%d) does not match expected (%d)", code, tt.expectedCode)
+				t.Fail()
+			}
+			if !strings.Contains(stderr.String(), tt.expectedErrContains) {
+				t.Logf("unexpected stderr: %s", stderr.String())
+				t.Fail()
+			}
+		})
+	}
+}
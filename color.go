@@ -0,0 +1,93 @@
+package env
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+)
+
+// Color is a destination for an sRGB color, parsed from hex notation, e.g. `#ff8800`, `#f80` or with alpha `#ff880080`, or from functional notation,
+// e.g. `rgb(255,136,0)` or `rgba(255,136,0,0.5)` with an alpha between 0 and 1. It implements color.Color, with non-premultiplied components as in CSS.
+//
+// As functional notation contains commas, lists of colors are not supported.
+type Color struct {
+	R, G, B, A uint8
+}
+
+// RGBA implements color.Color.
+func (c Color) RGBA() (r, g, b, a uint32) {
+	return color.NRGBA{R: c.R, G: c.G, B: c.B, A: c.A}.RGBA()
+}
+
+// String returns the color in hex notation, including the alpha only when the color is not opaque.
+func (c Color) String() string {
+	if c.A == 0xff {
+		return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+	}
+	return fmt.Sprintf("#%02x%02x%02x%02x", c.R, c.G, c.B, c.A)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (c *Color) UnmarshalText(text []byte) error {
+	s := strings.ToLower(strings.TrimSpace(string(text)))
+	if hex, ok := strings.CutPrefix(s, "#"); ok {
+		return c.fromHex(hex, string(text))
+	}
+	if args, ok := strings.CutPrefix(s, "rgba("); ok {
+		return c.fromFunc(args, 4, string(text))
+	}
+	if args, ok := strings.CutPrefix(s, "rgb("); ok {
+		return c.fromFunc(args, 3, string(text))
+	}
+	return fmt.Errorf("invalid color %q (want e.g. #ff8800 or rgb(255,136,0))", text)
+}
+
+// fromHex parses 3, 4, 6 or 8 hex digits, the short forms repeating each digit.
+func (c *Color) fromHex(hex, text string) error {
+	if len(hex) == 3 || len(hex) == 4 {
+		var long strings.Builder
+		for i := range len(hex) {
+			long.WriteString(hex[i : i+1])
+			long.WriteString(hex[i : i+1])
+		}
+		hex = long.String()
+	}
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+	n, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil || len(hex) != 8 {
+		return fmt.Errorf("invalid hex color %q (want 3, 4, 6 or 8 hex digits)", text)
+	}
+	*c = Color{R: uint8(n >> 24), G: uint8(n >> 16), B: uint8(n >> 8), A: uint8(n)}
+	return nil
+}
+
+// fromFunc parses the arguments of rgb() or rgba(): components between 0 and 255, then for rgba() an alpha between 0 and 1.
+func (c *Color) fromFunc(args string, want int, text string) error {
+	args, ok := strings.CutSuffix(args, ")")
+	parts := strings.Split(args, ",")
+	if !ok || len(parts) != want {
+		return fmt.Errorf("invalid color %q (want %d components)", text, want)
+	}
+
+	var rgb [3]uint8
+	for i := range rgb {
+		n, err := strconv.ParseUint(strings.TrimSpace(parts[i]), 10, 8)
+		if err != nil {
+			return fmt.Errorf("invalid color %q: component %q is not between 0 and 255", text, strings.TrimSpace(parts[i]))
+		}
+		rgb[i] = uint8(n)
+	}
+	alpha := uint8(0xff)
+	if want == 4 {
+		a, err := strconv.ParseFloat(strings.TrimSpace(parts[3]), 64)
+		if err != nil || !(a >= 0 && a <= 1) {
+			return fmt.Errorf("invalid color %q: alpha %q is not between 0 and 1", text, strings.TrimSpace(parts[3]))
+		}
+		alpha = uint8(a*0xff + 0.5)
+	}
+	*c = Color{R: rgb[0], G: rgb[1], B: rgb[2], A: alpha}
+	return nil
+}
@@ -0,0 +1,63 @@
+package env_test
+
+import (
+	"context"
+	"image/color"
+	"strings"
+	"testing"
+
+	"github.com/ndisidore/go-env"
+)
+
+func TestColor(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name                string
+		value               string
+		expected            env.Color
+		expectedString      string
+		expectedErrContains string
+	}{
+		{name: "hex", value: "#FF8800", expected: env.Color{R: 0xff, G: 0x88, A: 0xff}, expectedString: "#ff8800"},
+		{name: "short hex", value: "#f80", expected: env.Color{R: 0xff, G: 0x88, A: 0xff}, expectedString: "#ff8800"},
+		{name: "hex alpha", value: "#ff880080", expected: env.Color{R: 0xff, G: 0x88, A: 0x80}, expectedString: "#ff880080"},
+		{name: "short hex alpha", value: "#f808", expected: env.Color{R: 0xff, G: 0x88, A: 0x88}, expectedString: "#ff880088"},
+		{name: "rgb", value: "rgb(1, 2, 3)", expected: env.Color{R: 1, G: 2, B: 3, A: 0xff}, expectedString: "#010203"},
+		{name: "rgba", value: "RGBA(1,2,3,0.5)", expected: env.Color{R: 1, G: 2, B: 3, A: 0x80}, expectedString: "#01020380"},
+		{name: "bad hex", value: "#ff88zz", expectedErrContains: "want 3, 4, 6 or 8 hex digits"},
+		{name: "odd hex", value: "#ff880", expectedErrContains: "want 3, 4, 6 or 8 hex digits"},
+		{name: "big component", value: "rgb(256,0,0)", expectedErrContains: `component "256" is not between 0 and 255`},
+		{name: "missing component", value: "rgb(1,2)", expectedErrContains: "want 3 components"},
+		{name: "bad alpha", value: "rgba(1,2,3,2)", expectedErrContains: `alpha "2" is not between 0 and 1`},
+		{name: "name", value: "orange", expectedErrContains: "want e.g. #ff8800"},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ret, err := env.FromEnvOrDefaultAny(context.Background(), "COLOR", env.Color{}, env.WithEnvLoader(func(string) string { return tt.value }))
+			switch {
+			case err != nil && tt.expectedErrContains == "":
+				t.Logf("unexpected error: %v", err)
+				t.Fail()
+			case err != nil:
+				if !strings.Contains(err.Error(), tt.expectedErrContains) {
+					t.Logf("error (%v) does not contain expected (%s)", err, tt.expectedErrContains)
+					t.Fail()
+				}
+			case tt.expectedErrContains != "":
+				t.Logf("expected error containing %q", tt.expectedErrContains)
+				t.Fail()
+			case ret != tt.expected || ret.String() != tt.expectedString:
+				t.Logf("return value (%v) does not match expected (%v)", ret, tt.expected)
+				t.Fail()
+			}
+		})
+	}
+
+	// color.Color conversions treat the components as non-premultiplied
+	if c := color.RGBAModel.Convert(env.Color{R: 0xff, A: 0x80}).(color.RGBA); c != (color.RGBA{R: 0x80, A: 0x80}) {
+		t.Logf("unexpected conversion: %v", c)
+		t.Fail()
+	}
+}
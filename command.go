@@ -0,0 +1,44 @@
+//go:build !tinygo
+
+package env
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+)
+
+// Environ resolves each of the keys using the parser, returning those that are set in the `KEY=value` form of os.Environ.
+func (p *Parser) Environ(ctx context.Context, keys ...string) ([]string, error) {
+	environ := make([]string, 0, len(keys))
+	var errs Errors
+	for _, key := range keys {
+		val, err := GetOrNil[string](ctx, p, key)
+		if err != nil {
+			errs.Append(err)
+			continue
+		}
+		if val != nil {
+			environ = append(environ, key+"="+*val)
+		}
+	}
+	return environ, errs.ErrOrNil()
+}
+
+// Command returns an exec.Cmd, as by exec.CommandContext, whose environment is the current process environment overlaid with the keys resolved by the parser,
+// e.g. to run a child process against config held in a dotenv file or a secret store. Unset keys are inherited from the current process unchanged.
+func (p *Parser) Command(ctx context.Context, keys []string, name string, arg ...string) (*exec.Cmd, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("command keys cannot be empty")
+	}
+	environ, err := p.Environ(ctx, keys...)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := exec.CommandContext(ctx, name, arg...)
+	// later entries take precedence, so the resolved values override those inherited
+	cmd.Env = append(os.Environ(), environ...)
+	return cmd, nil
+}
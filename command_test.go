@@ -0,0 +1,53 @@
+//go:build !tinygo
+
+package env_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/ndisidore/go-env"
+)
+
+func TestParserEnviron(t *testing.T) {
+	t.Parallel()
+
+	p, err := env.NewParser(env.WithEnvLoader(func(key string) string {
+		return map[string]string{"DATABASE_URL": "postgres://db", "PORT": "8080\r"}[key]
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	environ, err := p.Environ(context.Background(), "DATABASE_URL", "UNSET", "PORT")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := []string{"DATABASE_URL=postgres://db", "PORT=8080"}; !slices.Equal(environ, expected) {
+		t.Logf("return value (%v) does not match expected (%v)", environ, expected)
+		t.Fail()
+	}
+
+	cmd, err := p.Command(context.Background(), []string{"PORT"}, "true")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cmd.Env[len(cmd.Env)-1] != "PORT=8080" {
+		t.Logf("resolved keys should override the inherited environment: %v", cmd.Env)
+		t.Fail()
+	}
+
+	strict, err := env.NewParser(env.WithEnvLoader(func(string) string { return "" }), env.WithKeyPolicy(env.POSIXKeys))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := strict.Environ(context.Background(), "PORT", "bad name"); err == nil {
+		t.Log("expected an error for a key rejected by the key policy")
+		t.Fail()
+	}
+	if _, err := p.Command(context.Background(), nil, "true"); err == nil {
+		t.Log("expected an error for empty keys")
+		t.Fail()
+	}
+}
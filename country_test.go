@@ -0,0 +1,40 @@
+package env_test
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ndisidore/go-env"
+)
+
+func TestCountryCode(t *testing.T) {
+	t.Parallel()
+
+	loader := func(key string) string {
+		return map[string]string{"HOME": "de", "ALLOWED": "US, ca,MX", "BAD": "XX", "BAD_LIST": "US,USA"}[key]
+	}
+	ctx := context.Background()
+
+	if ret, err := env.FromEnvOrDefault(ctx, "HOME", env.CountryCode("US"), env.WithEnvLoader(loader)); err != nil || ret != "DE" {
+		t.Logf("FromEnvOrDefault returned (%q, %v)", ret, err)
+		t.Fail()
+	}
+	if ret, err := env.FromEnvOrDefault(ctx, "ALLOWED", []env.CountryCode{}, env.WithEnvLoader(loader)); err != nil || !reflect.DeepEqual(ret, []env.CountryCode{"US", "CA", "MX"}) {
+		t.Logf("FromEnvOrDefault returned (%v, %v)", ret, err)
+		t.Fail()
+	}
+	if _, err := env.FromEnvOrDefault(ctx, "BAD", env.CountryCode(""), env.WithEnvLoader(loader)); err == nil || !strings.Contains(err.Error(), `invalid country code "XX"`) {
+		t.Logf("unexpected error: %v", err)
+		t.Fail()
+	}
+	if _, err := env.FromEnvOrDefault(ctx, "BAD_LIST", []env.CountryCode{}, env.WithEnvLoader(loader)); err == nil || !strings.Contains(err.Error(), "item USA (pos: 1) failed to parse") {
+		t.Logf("unexpected error: %v", err)
+		t.Fail()
+	}
+	if ret, err := env.FromEnvOrDefault(ctx, "ALLOWED", []env.CountryCode{}, env.WithEnvLoader(loader), env.WithAllowedValues[env.CountryCode]("US", "CA")); err == nil {
+		t.Logf("expected MX to be rejected, got %v", ret)
+		t.Fail()
+	}
+}
@@ -0,0 +1,61 @@
+package env
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+)
+
+type (
+	deprecatedKey struct {
+		key          string
+		removedAfter time.Time
+	}
+
+	// DeprecationOption customizes how a deprecated key passed to WithDeprecatedKey is handled.
+	DeprecationOption func(d *deprecatedKey)
+)
+
+// RemovedAfter sets the sunset date of a deprecated key. Once the date has passed, reading the deprecated key is an error rather than a warning.
+func RemovedAfter(date time.Time) DeprecationOption {
+	return func(d *deprecatedKey) {
+		d.removedAfter = date
+	}
+}
+
+// WithDeprecatedKey informs the parser that the env var was previously named `old`. If the env var itself is unset, the value of the old key is used and a warning is reported (see WithWarningHandler).
+//
+// Multiple deprecated keys may be provided; they are consulted in the order given.
+func WithDeprecatedKey(old string, opts ...DeprecationOption) EnvParseOption {
+	return func(o *envParseOpts) error {
+		if old == "" {
+			return errors.New("deprecated key cannot be empty string")
+		}
+
+		d := deprecatedKey{key: old}
+		for _, opt := range opts {
+			opt(&d)
+		}
+		// clip so per-call options never append into a parser's backing array
+		o.deprecatedKeys = append(slices.Clip(o.deprecatedKeys), d)
+		return nil
+	}
+}
+
+// loadDeprecated consults the deprecated keys in order, returning the first value found.
+func (o *envParseOpts) loadDeprecated(ctx context.Context, envVar string, raw bool) (string, error) {
+	for _, d := range o.deprecatedKeys {
+		val := o.load(envVar, d.key, raw)
+		if val == "" {
+			continue
+		}
+		if !d.removedAfter.IsZero() && o.clock.Now().After(d.removedAfter) {
+			return "", errors.New(o.message(MsgKeyRemoved, d.key, d.removedAfter.Format(time.DateOnly), envVar))
+		}
+
+		o.warnDeprecated(ctx, envVar, d.key)
+		return val, nil
+	}
+	return "", nil
+}
@@ -0,0 +1,49 @@
+package env_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ndisidore/go-env"
+)
+
+func TestWithDeprecatedKey(t *testing.T) {
+	t.Parallel()
+
+	loader := func(key string) string {
+		return map[string]string{"OLD_PORT": "8080", "NEW_HOST": "new", "OLD_HOST": "old"}[key]
+	}
+	cases := []struct {
+		searchEnv           string
+		options             []env.EnvParseOption
+		expected            string
+		expectedErrContains string
+	}{
+		{searchEnv: "PORT", options: []env.EnvParseOption{env.WithDeprecatedKey("OLD_PORT")}, expected: "8080"},
+		{searchEnv: "PORT", options: []env.EnvParseOption{env.WithDeprecatedKey("UNSET"), env.WithDeprecatedKey("OLD_PORT")}, expected: "8080"},
+		{searchEnv: "PORT", options: []env.EnvParseOption{env.WithDeprecatedKey("OLD_PORT", env.RemovedAfter(time.Now().Add(time.Hour)))}, expected: "8080"},
+		{searchEnv: "PORT", options: []env.EnvParseOption{env.WithDeprecatedKey("OLD_PORT", env.RemovedAfter(time.Now().Add(-time.Hour)))}, expectedErrContains: "OLD_PORT was removed"},
+		{searchEnv: "NEW_HOST", options: []env.EnvParseOption{env.WithDeprecatedKey("OLD_HOST")}, expected: "new"},
+		{searchEnv: "PORT", expected: "default"},
+	}
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			ret, err := env.FromEnvOrDefault(context.Background(), tt.searchEnv, "default", append(tt.options, env.WithEnvLoader(loader))...)
+			switch {
+			case err != nil && tt.expectedErrContains != "":
+				if !strings.Contains(err.Error(), tt.expectedErrContains) {
+					t.Logf("unexpected error: %v", err)
+					t.Fail()
+				}
+			case err != nil:
+				t.Logf("unexpected error: %v", err)
+				t.Fail()
+			case ret != tt.expected:
+				t.Logf("return value (%s) does not match expected (%s)", ret, tt.expected)
+				t.Fail()
+			}
+		})
+	}
+}
@@ -0,0 +1,8 @@
+// Package env loads typed values from the environment using generics.
+//
+// # tinygo
+//
+// The parsing core resolves destination types with a generic type switch rather than reflection so it can be compiled with tinygo,
+// e.g. for firmware or edge binaries reading env-like key stores through WithEnvLoader.
+// Features that require reflection beyond this core must live in files guarded by the `!tinygo` build constraint so that the `tinygo` build tag yields the switch-only parser.
+package env
@@ -0,0 +1,189 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// AppEnvKey is the env var naming the current environment, e.g. `development` or `production`, which selects the overlay read by DotEnvLayered.
+const AppEnvKey = "APP_ENV"
+
+// DotEnvLoader reads the dotenv file at path and returns a loader serving its values, for use with WithEnvLoader.
+//
+// The file is read once. Blank lines and lines starting with `#` are ignored, as is an `export ` prefix on keys. Unquoted values are trimmed and may carry a trailing ` #` comment;
+// single-quoted values are taken literally and double-quoted values support the `\n`, `\r`, `\t`, `\"` and `\\` escapes. Quoted values may span multiple lines.
+func DotEnvLoader(path string) (EnvLoader, error) {
+	loader, _, err := DotEnvLister(path)
+	return loader, err
+}
+
+// DotEnvLister reads the dotenv file at path as DotEnvLoader does, also returning a KeyLister enumerating its keys for use with WithKeyLister,
+// so that key discovery via Parser.Keys works for dotenv sources.
+func DotEnvLister(path string) (EnvLoader, KeyLister, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read dotenv file: %w", err)
+	}
+
+	vals, err := parseDotEnv(string(data))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse dotenv file %s: %w", path, err)
+	}
+	return mapLoader(vals), mapLister(vals), nil
+}
+
+// DotEnvLayered reads the dotenv files `.env`, `.env.<APP_ENV>` and `.env.local` in dir, in increasing order of precedence, and returns a loader serving the merged values.
+// APP_ENV is read from the process environment and the environment specific file is skipped when it is unset, as is any file that does not exist.
+//
+// As with DotEnvLoader, the files are read once. Layer the result beneath the process environment with ChainLoaders so real env vars still win.
+func DotEnvLayered(dir string) (EnvLoader, error) {
+	loader, _, err := DotEnvLayeredLister(dir)
+	return loader, err
+}
+
+// DotEnvLayeredLister reads the dotenv files in dir as DotEnvLayered does, also returning a KeyLister enumerating the merged keys for use with WithKeyLister.
+func DotEnvLayeredLister(dir string) (EnvLoader, KeyLister, error) {
+	names := []string{".env"}
+	if appEnv := os.Getenv(AppEnvKey); appEnv != "" {
+		if strings.ContainsAny(appEnv, `/\`) {
+			return nil, nil, fmt.Errorf("invalid %s %q: must not contain a path separator", AppEnvKey, appEnv)
+		}
+		names = append(names, ".env."+appEnv)
+	}
+	names = append(names, ".env.local")
+
+	vals := make(map[string]string)
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		data, err := os.ReadFile(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read dotenv file: %w", err)
+		}
+
+		layer, err := parseDotEnv(string(data))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse dotenv file %s: %w", path, err)
+		}
+		for k, v := range layer {
+			vals[k] = v
+		}
+	}
+	return mapLoader(vals), mapLister(vals), nil
+}
+
+// mapLoader returns a loader serving the values in vals.
+func mapLoader(vals map[string]string) EnvLoader {
+	return func(key string) string {
+		return vals[key]
+	}
+}
+
+// mapLister returns a lister enumerating the keys in vals.
+func mapLister(vals map[string]string) KeyLister {
+	return func() []string {
+		keys := make([]string, 0, len(vals))
+		for k := range vals {
+			keys = append(keys, k)
+		}
+		return keys
+	}
+}
+
+// parseDotEnv parses the contents of a dotenv file. Later assignments to a key override earlier ones.
+func parseDotEnv(data string) (map[string]string, error) {
+	vals := make(map[string]string)
+	lines := strings.Split(strings.ReplaceAll(normalize(data), "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		lineNo := i + 1
+		line := strings.TrimSpace(lines[i])
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		line = strings.TrimPrefix(line, "export ")
+		key, rest, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("line %d: expected KEY=VALUE", lineNo)
+		}
+
+		rest = strings.TrimLeft(rest, " \t")
+		if rest == "" || (rest[0] != '"' && rest[0] != '\'') {
+			if idx := strings.Index(rest, " #"); idx >= 0 {
+				rest = rest[:idx]
+			}
+			vals[key] = strings.TrimSpace(rest)
+			continue
+		}
+
+		// quoted values may continue onto following lines until the closing quote
+		quote := rest[0]
+		body := rest[1:]
+		end := closingQuote(body, quote)
+		for end < 0 {
+			i++
+			if i >= len(lines) {
+				return nil, fmt.Errorf("line %d: unterminated quoted value", lineNo)
+			}
+			body += "\n" + lines[i]
+			end = closingQuote(body, quote)
+		}
+		if trailing := strings.TrimSpace(body[end+1:]); trailing != "" && !strings.HasPrefix(trailing, "#") {
+			return nil, fmt.Errorf("line %d: unexpected content after quoted value", lineNo)
+		}
+
+		body = body[:end]
+		if quote == '"' {
+			body = unescapeDotEnv(body)
+		}
+		vals[key] = body
+	}
+	return vals, nil
+}
+
+// closingQuote returns the index of the first unescaped quote in s, or -1. Backslash escapes only apply within double quotes.
+func closingQuote(s string, quote byte) int {
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && quote == '"':
+			i++
+		case s[i] == quote:
+			return i
+		}
+	}
+	return -1
+}
+
+// unescapeDotEnv expands the escapes supported in double-quoted values. Unknown escapes are kept as-is.
+func unescapeDotEnv(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			sb.WriteByte(s[i])
+			continue
+		}
+
+		i++
+		switch s[i] {
+		case 'n':
+			sb.WriteByte('\n')
+		case 'r':
+			sb.WriteByte('\r')
+		case 't':
+			sb.WriteByte('\t')
+		case '"', '\\':
+			sb.WriteByte(s[i])
+		default:
+			sb.WriteByte('\\')
+			sb.WriteByte(s[i])
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,157 @@
+package env_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/ndisidore/go-env"
+)
+
+func TestDotEnvLoader(t *testing.T) {
+	t.Parallel()
+
+	const contents = "# comment\n" +
+		"export HOST=example.com\n" +
+		"PORT = 8080 # inline comment\n" +
+		"\n" +
+		"SINGLE='literal $HOME \\n'\n" +
+		"DOUBLE=\"tab\\tquote\\\" done\"\n" +
+		"CERT=\"-----BEGIN-----\n" +
+		"abc\n" +
+		"-----END-----\"\n" +
+		"HASH=a#b\n" +
+		"EMPTY=\n" +
+		"HOST=override.com\r\n"
+	path := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	loader, err := env.DotEnvLoader(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cases := []struct {
+		key      string
+		expected string
+	}{
+		{key: "HOST", expected: "override.com"},
+		{key: "PORT", expected: "8080"},
+		{key: "SINGLE", expected: `literal $HOME \n`},
+		{key: "DOUBLE", expected: "tab\tquote\" done"},
+		{key: "CERT", expected: "-----BEGIN-----\nabc\n-----END-----"},
+		{key: "HASH", expected: "a#b"},
+		{key: "EMPTY", expected: ""},
+		{key: "UNKNOWN", expected: ""},
+	}
+	for _, tt := range cases {
+		if ret := loader(tt.key); ret != tt.expected {
+			t.Logf("value of %s (%q) does not match expected (%q)", tt.key, ret, tt.expected)
+			t.Fail()
+		}
+	}
+
+	port, err := env.FromEnvOrDefault(context.Background(), "PORT", 0, env.WithEnvLoader(loader))
+	if err != nil || port != 8080 {
+		t.Logf("FromEnvOrDefault returned (%d, %v)", port, err)
+		t.Fail()
+	}
+
+	loader, lister, err := env.DotEnvLister(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	p, err := env.NewParser(env.WithEnvLoader(loader), env.WithKeyLister(lister))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if keys, err := p.Keys("*"); err != nil || !slices.Equal(keys, []string{"CERT", "DOUBLE", "EMPTY", "HASH", "HOST", "PORT", "SINGLE"}) {
+		t.Logf("Keys returned (%v, %v)", keys, err)
+		t.Fail()
+	}
+}
+
+func TestDotEnvLoaderErrors(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		contents            string
+		expectedErrContains string
+	}{
+		{contents: "NOVALUE\n", expectedErrContains: "line 1: expected KEY=VALUE"},
+		{contents: "A=1\nB=\"open\n", expectedErrContains: "line 2: unterminated quoted value"},
+		{contents: "A='x' y\n", expectedErrContains: "line 1: unexpected content"},
+	}
+	for _, tt := range cases {
+		path := filepath.Join(t.TempDir(), ".env")
+		if err := os.WriteFile(path, []byte(tt.contents), 0o600); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := env.DotEnvLoader(path); err == nil || !strings.Contains(err.Error(), tt.expectedErrContains) {
+			t.Logf("unexpected error: %v", err)
+			t.Fail()
+		}
+	}
+
+	if _, err := env.DotEnvLoader(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Log("expected an error for a missing file")
+		t.Fail()
+	}
+}
+
+func TestDotEnvLayered(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		".env":             "HOST=base.example.com\nPORT=8080\nDEBUG=false\nNAME=svc\n",
+		".env.staging":     "HOST=staging.example.com\nDEBUG=true\n",
+		".env.production":  "HOST=prod.example.com\n",
+		".env.local":       "DEBUG=false\n",
+		".env.staging.bak": "NAME=ignored\n",
+	}
+	for name, contents := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0o600); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	t.Setenv(env.AppEnvKey, "staging")
+	loader, err := env.DotEnvLayered(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for key, expected := range map[string]string{"HOST": "staging.example.com", "PORT": "8080", "DEBUG": "false", "NAME": "svc"} {
+		if val := loader(key); val != expected {
+			t.Logf("%s: return value (%q) does not match expected (%q)", key, val, expected)
+			t.Fail()
+		}
+	}
+
+	_, lister, err := env.DotEnvLayeredLister(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if keys := lister(); len(keys) != 4 {
+		t.Logf("expected the merged keys to be listed once each, got %v", keys)
+		t.Fail()
+	}
+
+	t.Setenv(env.AppEnvKey, "")
+	if loader, err := env.DotEnvLayered(dir); err != nil || loader("HOST") != "base.example.com" {
+		t.Logf("without %s only the base and local files should be read, got err %v", env.AppEnvKey, err)
+		t.Fail()
+	}
+	if loader, err := env.DotEnvLayered(t.TempDir()); err != nil || loader("HOST") != "" {
+		t.Logf("missing files should be skipped, got err %v", err)
+		t.Fail()
+	}
+
+	t.Setenv(env.AppEnvKey, "../secrets")
+	if _, err := env.DotEnvLayered(dir); err == nil || !strings.Contains(err.Error(), "path separator") {
+		t.Logf("unexpected error: %v", err)
+		t.Fail()
+	}
+}
@@ -0,0 +1,109 @@
+//go:build !tinygo
+
+package env
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"time"
+)
+
+// Drift describes a struct field whose resolved value no longer matches the snapshot taken when drift watching began.
+type Drift struct {
+	// Field is the field name relative to the outermost struct, e.g. `Database.Port`.
+	Field string
+	// EnvVar is the env var named by the field's tag.
+	EnvVar string
+	// Immutable reports whether the field is tagged `immutable`, i.e. the change cannot be applied without a restart.
+	Immutable bool
+}
+
+// WatchDrift snapshots the struct pointed to by dest, typically just after it was populated by Unmarshal at boot, then re-resolves it every interval
+// and emits the set of drifted fields whenever it changes. An empty set is emitted once drift is resolved.
+//
+// dest itself is never read again or modified. Resolution errors are logged and the tick skipped. The channel is closed once the context is done.
+func (p *Parser) WatchDrift(ctx context.Context, dest any, interval time.Duration, opts ...EnvParseOption) (<-chan []Drift, error) {
+	if interval <= 0 {
+		return nil, errors.New("drift interval must be positive")
+	}
+	parseOpts := p.opts
+	for _, opt := range opts {
+		if err := opt(&parseOpts); err != nil {
+			return nil, fmt.Errorf("option error: %w", err)
+		}
+	}
+
+	rv := reflect.ValueOf(dest)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("drift destination must be a non-nil pointer to a struct, got %T", dest)
+	}
+	snapshot := reflect.New(rv.Elem().Type()).Elem()
+	snapshot.Set(rv.Elem())
+
+	ch := make(chan []Drift, 1)
+	go func() {
+		defer close(ch)
+		ticker := parseOpts.clock.NewTicker(interval)
+		defer ticker.Stop()
+		reported := make([]Drift, 0)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C():
+			}
+
+			current := reflect.New(snapshot.Type()).Elem()
+			current.Set(snapshot)
+			fe := fieldErrors{limit: parseOpts.maxErrors}
+			unmarshalStruct(ctx, &parseOpts, "", current, &fe)
+			if err := fe.err(); err != nil {
+				slog.Default().WarnContext(ctx, "failed to resolve config for drift detection", slog.String("error", err.Error()))
+				continue
+			}
+
+			drifts := make([]Drift, 0)
+			diffTagged("", snapshot, current, &drifts)
+			if reflect.DeepEqual(drifts, reported) {
+				continue
+			}
+			reported = drifts
+
+			select {
+			case <-ctx.Done():
+				return
+			case ch <- drifts:
+			}
+		}
+	}()
+
+	return ch, nil
+}
+
+// diffTagged appends a Drift for each tagged field that differs between the two values of the same struct type.
+func diffTagged(path string, before, after reflect.Value, drifts *[]Drift) {
+	st := before.Type()
+	for i := 0; i < st.NumField(); i++ {
+		sf := st.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		tag, tagged := sf.Tag.Lookup("env")
+		switch {
+		case tag == "-":
+		case !tagged && sf.Type.Kind() == reflect.Struct && !isCustomDest(before.Field(i).Addr().Interface()):
+			diffTagged(path+sf.Name+".", before.Field(i), after.Field(i), drifts)
+		case !tagged:
+		default:
+			ft, err := parseFieldTag(tag)
+			if err != nil || reflect.DeepEqual(before.Field(i).Interface(), after.Field(i).Interface()) {
+				continue
+			}
+			*drifts = append(*drifts, Drift{Field: path + sf.Name, EnvVar: ft.key, Immutable: ft.immutable})
+		}
+	}
+}
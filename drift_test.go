@@ -0,0 +1,77 @@
+//go:build !tinygo
+
+package env_test
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ndisidore/go-env"
+)
+
+func TestParserWatchDrift(t *testing.T) {
+	t.Parallel()
+
+	type config struct {
+		Port     int `env:"PORT,immutable"`
+		Database struct {
+			Host string `env:"DB_HOST"`
+		}
+	}
+	var (
+		mu   sync.Mutex
+		vals = map[string]string{"PORT": "8080", "DB_HOST": "primary"}
+	)
+	set := func(key, val string) {
+		mu.Lock()
+		defer mu.Unlock()
+		vals[key] = val
+	}
+	loader := func(key string) string {
+		mu.Lock()
+		defer mu.Unlock()
+		return vals[key]
+	}
+	clock := newFakeClock(time.Now())
+	p, err := env.NewParser(env.WithEnvLoader(loader), env.WithClock(clock))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var cfg config
+	if err := p.Unmarshal(context.Background(), &cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch, err := p.WatchDrift(ctx, &cfg, time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	set("PORT", "9090")
+	set("DB_HOST", "replica")
+	clock.Tick(time.Minute)
+	expected := []env.Drift{{Field: "Port", EnvVar: "PORT", Immutable: true}, {Field: "Database.Host", EnvVar: "DB_HOST"}}
+	if got := <-ch; !reflect.DeepEqual(got, expected) {
+		t.Logf("drift (%+v) does not match expected (%+v)", got, expected)
+		t.Fail()
+	}
+
+	// an unchanged drift set is not re-emitted
+	clock.Tick(time.Minute)
+	set("PORT", "8080")
+	set("DB_HOST", "primary")
+	clock.Tick(time.Minute)
+	if got := <-ch; len(got) != 0 {
+		t.Logf("expected drift to be resolved, got %+v", got)
+		t.Fail()
+	}
+
+	cancel()
+	for range ch {
+	}
+}
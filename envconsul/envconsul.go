@@ -0,0 +1,172 @@
+// Package envconsul resolves environment variables from Consul KV, so configuration stored in Consul can be read through the same calls as the environment.
+package envconsul
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/ndisidore/go-env"
+)
+
+// DefaultAddress is the address of the local Consul agent's HTTP API.
+const DefaultAddress = "http://127.0.0.1:8500"
+
+const (
+	// retryDelay is how long a watching loader waits after a failed blocking query before retrying.
+	retryDelay = 5 * time.Second
+	// minResetDelay is how long a watching loader first waits after its index is reset, doubling up to retryDelay while resets continue.
+	minResetDelay = time.Second
+)
+
+// Config locates the keys in Consul KV.
+type Config struct {
+	// Address is the base URL of the Consul HTTP API, DefaultAddress if empty.
+	Address string
+	// Prefix is the KV path holding the keys, e.g. `config/myapp/`. A key is resolved by appending it to the prefix, so `PORT` reads `config/myapp/PORT`.
+	Prefix string
+	// Token is sent as the ACL token of each request when set.
+	Token string
+	// Client makes the requests, http.DefaultClient if nil.
+	Client *http.Client
+}
+
+// kvPair is an entry of a Consul KV listing. Values are base64 encoded, which encoding/json decodes into a []byte, and null for keys without one.
+type kvPair struct {
+	Key   string
+	Value []byte
+}
+
+// Loader reads every key under the configured prefix once, returning a loader that resolves keys from that snapshot.
+func Loader(ctx context.Context, cfg Config) (env.EnvLoader, error) {
+	kv, _, err := cfg.list(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+	return func(key string) string {
+		return kv[key]
+	}, nil
+}
+
+// WatchingLoader reads every key under the configured prefix, then keeps the snapshot current with Consul blocking queries until the context is done,
+// so changes are picked up without a restart. Failed refreshes are logged, retried, and leave the previous snapshot in place.
+func WatchingLoader(ctx context.Context, cfg Config) (env.EnvLoader, error) {
+	kv, index, err := cfg.list(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var current atomic.Pointer[map[string]string]
+	current.Store(&kv)
+	index = sanitizeIndex(index, 0)
+	go func() {
+		resetDelay := minResetDelay
+		for ctx.Err() == nil {
+			next, nextIndex, err := cfg.list(ctx, index)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				slog.Default().WarnContext(ctx, "failed to refresh consul keys", slog.String("prefix", cfg.Prefix), slog.String("error", err.Error()))
+				select {
+				case <-ctx.Done():
+				case <-time.After(retryDelay):
+				}
+				continue
+			}
+			current.Store(&next)
+			// an unchanged index is a blocking query whose wait elapsed, which is retried at once. A missing or regressed index is reset,
+			// and as the reset query would not block, backs off rather than spin against the server.
+			reset := nextIndex == 0 || nextIndex < index
+			index = sanitizeIndex(nextIndex, index)
+			if !reset {
+				resetDelay = minResetDelay
+				continue
+			}
+
+			select {
+			case <-ctx.Done():
+			case <-time.After(resetDelay):
+			}
+			resetDelay = min(2*resetDelay, retryDelay)
+		}
+	}()
+
+	return func(key string) string {
+		return (*current.Load())[key]
+	}, nil
+}
+
+// sanitizeIndex returns the index to block on after a query returned next, following Consul's guidance for blocking queries:
+// an index that went backwards, e.g. after a snapshot restore, or that is missing or zero is reset to 1, since an index of 0 would not block at all.
+func sanitizeIndex(next, prev uint64) uint64 {
+	if next < prev || next == 0 {
+		return 1
+	}
+	return next
+}
+
+// list reads every key under the prefix, blocking until the KV index passes index when it is non-zero. Keys are returned relative to the prefix.
+func (cfg Config) list(ctx context.Context, index uint64) (map[string]string, uint64, error) {
+	addr := cfg.Address
+	if addr == "" {
+		addr = DefaultAddress
+	}
+	query := url.Values{"recurse": {"true"}}
+	if index > 0 {
+		query.Set("index", strconv.FormatUint(index, 10))
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(addr, "/")+"/v1/kv/"+strings.TrimPrefix(cfg.Prefix, "/")+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to build consul request: %w", err)
+	}
+	if cfg.Token != "" {
+		req.Header.Set("X-Consul-Token", cfg.Token)
+	}
+
+	client := cfg.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query consul: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// a missing or malformed index is reported as 0, which the watch resets
+	newIndex, err := strconv.ParseUint(resp.Header.Get("X-Consul-Index"), 10, 64)
+	if err != nil {
+		newIndex = 0
+	}
+	kv := make(map[string]string)
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		// nothing is stored under the prefix yet
+		return kv, newIndex, nil
+	default:
+		return nil, 0, fmt.Errorf("consul returned %s for prefix %q", resp.Status, cfg.Prefix)
+	}
+
+	var pairs []kvPair
+	if err := json.NewDecoder(resp.Body).Decode(&pairs); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode consul response: %w", err)
+	}
+	for _, p := range pairs {
+		key := strings.TrimPrefix(p.Key, strings.TrimPrefix(cfg.Prefix, "/"))
+		if key == "" || strings.HasSuffix(key, "/") {
+			// folders carry no value
+			continue
+		}
+		kv[key] = string(p.Value)
+	}
+	return kv, newIndex, nil
+}
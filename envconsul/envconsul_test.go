@@ -0,0 +1,161 @@
+package envconsul_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ndisidore/go-env"
+	"github.com/ndisidore/go-env/envconsul"
+)
+
+func TestLoader(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/kv/config/app/" || r.URL.Query().Get("recurse") != "true" || r.Header.Get("X-Consul-Token") != "secret" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("X-Consul-Index", "7")
+		_, _ = w.Write([]byte(`[{"Key":"config/app/","Value":null},{"Key":"config/app/PORT","Value":"ODA4MA=="},{"Key":"config/app/db/HOST","Value":"ZGI="}]`))
+	}))
+	t.Cleanup(srv.Close)
+
+	loader, err := envconsul.Loader(context.Background(), envconsul.Config{Address: srv.URL, Prefix: "config/app/", Token: "secret"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if port, err := env.FromEnvOrDefault(context.Background(), "PORT", 0, env.WithEnvLoader(loader)); err != nil || port != 8080 {
+		t.Logf("FromEnvOrDefault returned (%d, %v)", port, err)
+		t.Fail()
+	}
+	if host := loader("db/HOST"); host != "db" {
+		t.Logf("unexpected nested value: %q", host)
+		t.Fail()
+	}
+
+	if loader, err := envconsul.Loader(context.Background(), envconsul.Config{Address: srv.URL, Prefix: "missing/", Token: "secret"}); err != nil || loader("PORT") != "" {
+		t.Logf("expected an empty loader for a missing prefix, got error %v", err)
+		t.Fail()
+	}
+	if _, err := envconsul.Loader(context.Background(), envconsul.Config{Address: "http://127.0.0.1:0"}); err == nil {
+		t.Log("expected an error for an unreachable agent")
+		t.Fail()
+	}
+}
+
+func TestWatchingLoader(t *testing.T) {
+	t.Parallel()
+
+	var port atomic.Value
+	port.Store("ODA4MA==") // 8080
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("index") == "1" {
+			// the blocking query returns once the value changes
+			port.Store("OTA5MA==") // 9090
+			w.Header().Set("X-Consul-Index", "2")
+		} else if r.URL.Query().Get("index") != "" {
+			<-r.Context().Done()
+			return
+		} else {
+			w.Header().Set("X-Consul-Index", "1")
+		}
+		_, _ = w.Write([]byte(`[{"Key":"app/PORT","Value":"` + port.Load().(string) + `"}]`))
+	}))
+	t.Cleanup(srv.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	loader, err := envconsul.WatchingLoader(ctx, envconsul.Config{Address: srv.URL, Prefix: "app/"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for loader("PORT") != "9090" {
+		if time.Now().After(deadline) {
+			t.Fatalf("value was not refreshed, still %q", loader("PORT"))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestWatchingLoaderMissingIndex(t *testing.T) {
+	t.Parallel()
+
+	var (
+		requests atomic.Int32
+		indexes  = make(chan string, 100)
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		select {
+		case indexes <- r.URL.Query().Get("index"):
+		default:
+		}
+		// no X-Consul-Index header, as with a proxy stripping it, so the query never blocks
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	t.Cleanup(srv.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	if _, err := envconsul.WatchingLoader(ctx, envconsul.Config{Address: srv.URL, Prefix: "app/"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	time.Sleep(500 * time.Millisecond)
+	if n := requests.Load(); n > 3 {
+		t.Logf("watch sent %d requests without the index advancing, expected it to back off", n)
+		t.Fail()
+	}
+	<-indexes
+	if idx := <-indexes; idx != "1" {
+		t.Logf("expected the watch to clamp the missing index to 1, got %q", idx)
+		t.Fail()
+	}
+}
+
+func TestWatchingLoaderWaitElapsed(t *testing.T) {
+	t.Parallel()
+
+	var waits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		value := "ODA4MA==" // 8080
+		switch {
+		case r.URL.Query().Get("index") == "":
+			w.Header().Set("X-Consul-Index", "5")
+		case waits.Add(1) <= 3:
+			// the wait of a blocking query elapsed without a change, so the index is unchanged
+			w.Header().Set("X-Consul-Index", "5")
+		case r.URL.Query().Get("index") == "5":
+			w.Header().Set("X-Consul-Index", "6")
+			value = "OTA5MA==" // 9090
+		default:
+			<-r.Context().Done()
+			return
+		}
+		_, _ = w.Write([]byte(`[{"Key":"app/PORT","Value":"` + value + `"}]`))
+	}))
+	t.Cleanup(srv.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	loader, err := envconsul.WatchingLoader(ctx, envconsul.Config{Address: srv.URL, Prefix: "app/"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// elapsed waits are retried at once rather than backing off, which would take several seconds
+	deadline := time.Now().Add(500 * time.Millisecond)
+	for loader("PORT") != "9090" {
+		if time.Now().After(deadline) {
+			t.Fatalf("value was not refreshed promptly after elapsed waits, still %q", loader("PORT"))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
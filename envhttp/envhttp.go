@@ -0,0 +1,158 @@
+// Package envhttp resolves environment variables from a central config service, fetching them as a JSON object over HTTP(S).
+package envhttp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/ndisidore/go-env"
+)
+
+// DefaultTimeout bounds each request when Config.Timeout is unset.
+const DefaultTimeout = 10 * time.Second
+
+// Config locates the config service.
+type Config struct {
+	// URL returns a JSON object of keys to values. String values are used as is, while numbers, booleans, objects and arrays are used as their JSON text.
+	// Null values leave the key unset.
+	URL string
+	// Header is added to each request, e.g. an Authorization header.
+	Header http.Header
+	// Timeout bounds each request, DefaultTimeout if zero.
+	Timeout time.Duration
+	// Client makes the requests, http.DefaultClient if nil.
+	Client *http.Client
+	// Clock schedules the polls of PollingLoader, env.SystemClock if nil.
+	Clock env.Clock
+}
+
+// snapshot is the last fetched config and its ETag.
+type snapshot struct {
+	values map[string]string
+	etag   string
+}
+
+// Loader fetches the config once, returning a loader that resolves keys from that snapshot.
+func Loader(ctx context.Context, cfg Config) (env.EnvLoader, error) {
+	snap, err := cfg.fetch(ctx, snapshot{})
+	if err != nil {
+		return nil, err
+	}
+	return func(key string) string {
+		return snap.values[key]
+	}, nil
+}
+
+// PollingLoader fetches the config, then re-fetches it every interval until the context is done, so changes are picked up without a restart.
+// Re-fetches are conditional on the ETag of the last response, so an unchanged config is not transferred again. Failed fetches are logged and leave
+// the previous snapshot in place.
+func PollingLoader(ctx context.Context, cfg Config, interval time.Duration) (env.EnvLoader, error) {
+	if interval <= 0 {
+		return nil, errors.New("poll interval must be positive")
+	}
+	snap, err := cfg.fetch(ctx, snapshot{})
+	if err != nil {
+		return nil, err
+	}
+
+	var current atomic.Pointer[snapshot]
+	current.Store(&snap)
+	go func() {
+		clock := cfg.Clock
+		if clock == nil {
+			clock = env.SystemClock()
+		}
+		ticker := clock.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C():
+			}
+			next, err := cfg.fetch(ctx, *current.Load())
+			if err != nil {
+				if ctx.Err() == nil {
+					slog.Default().WarnContext(ctx, "failed to refresh remote config", slog.String("url", cfg.URL), slog.String("error", err.Error()))
+				}
+				continue
+			}
+			current.Store(&next)
+		}
+	}()
+
+	return func(key string) string {
+		return current.Load().values[key]
+	}, nil
+}
+
+// fetch requests the config, returning prev when the server reports it has not changed since prev's ETag.
+func (cfg Config) fetch(ctx context.Context, prev snapshot) (snapshot, error) {
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, cfg.URL, nil)
+	if err != nil {
+		return prev, fmt.Errorf("failed to build config request: %w", err)
+	}
+	for k, vs := range cfg.Header {
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+	req.Header.Set("Accept", "application/json")
+	if prev.etag != "" {
+		req.Header.Set("If-None-Match", prev.etag)
+	}
+
+	client := cfg.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return prev, fmt.Errorf("failed to fetch config: %w", err)
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotModified:
+		if prev.values != nil {
+			return prev, nil
+		}
+		fallthrough
+	default:
+		return prev, fmt.Errorf("config service returned %s", resp.Status)
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return prev, fmt.Errorf("failed to decode config (want a JSON object): %w", err)
+	}
+	values := make(map[string]string, len(raw))
+	for k, v := range raw {
+		switch {
+		case bytes.Equal(v, []byte("null")):
+		case len(v) > 0 && v[0] == '"':
+			var s string
+			if err := json.Unmarshal(v, &s); err != nil {
+				return prev, fmt.Errorf("failed to decode config key %s: %w", k, err)
+			}
+			values[k] = s
+		default:
+			values[k] = string(v)
+		}
+	}
+	return snapshot{values: values, etag: resp.Header.Get("ETag")}, nil
+}
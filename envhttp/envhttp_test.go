@@ -0,0 +1,133 @@
+package envhttp_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ndisidore/go-env"
+	"github.com/ndisidore/go-env/envhttp"
+)
+
+func TestLoader(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Header.Get("Authorization") != "Bearer t0ken":
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+		case r.URL.Path == "/slow":
+			<-r.Context().Done()
+		case r.URL.Path == "/list":
+			_, _ = w.Write([]byte(`["PORT"]`))
+		default:
+			_, _ = w.Write([]byte(`{"PORT":8080,"HOST":"db.internal","DEBUG":true,"LIMITS":{"a":1},"UNSET":null}`))
+		}
+	}))
+	t.Cleanup(srv.Close)
+	auth := http.Header{"Authorization": {"Bearer t0ken"}}
+
+	loader, err := envhttp.Loader(context.Background(), envhttp.Config{URL: srv.URL + "/config", Header: auth})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if port, err := env.FromEnvOrDefault(context.Background(), "PORT", 0, env.WithEnvLoader(loader)); err != nil || port != 8080 {
+		t.Logf("FromEnvOrDefault returned (%d, %v)", port, err)
+		t.Fail()
+	}
+	for key, expected := range map[string]string{"HOST": "db.internal", "DEBUG": "true", "LIMITS": `{"a":1}`, "UNSET": ""} {
+		if got := loader(key); got != expected {
+			t.Logf("value of %s (%q) does not match expected (%q)", key, got, expected)
+			t.Fail()
+		}
+	}
+
+	cases := []struct {
+		name                string
+		cfg                 envhttp.Config
+		expectedErrContains string
+	}{
+		{name: "unauthorized", cfg: envhttp.Config{URL: srv.URL}, expectedErrContains: "401 Unauthorized"},
+		{name: "timeout", cfg: envhttp.Config{URL: srv.URL + "/slow", Header: auth, Timeout: 10 * time.Millisecond}, expectedErrContains: "deadline exceeded"},
+		{name: "not an object", cfg: envhttp.Config{URL: srv.URL + "/list", Header: auth}, expectedErrContains: "want a JSON object"},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if _, err := envhttp.Loader(context.Background(), tt.cfg); err == nil || !strings.Contains(err.Error(), tt.expectedErrContains) {
+				t.Logf("unexpected error: %v", err)
+				t.Fail()
+			}
+		})
+	}
+}
+
+func TestPollingLoader(t *testing.T) {
+	t.Parallel()
+
+	var calls, notModified atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+		etag := `"v1"`
+		if n >= 3 {
+			etag = `"v2"`
+		}
+		if r.Header.Get("If-None-Match") == etag {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		_, _ = w.Write([]byte(`{"LEVEL":"` + map[string]string{`"v1"`: "info", `"v2"`: "debug"}[etag] + `"}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	clock := manualClock{ch: make(chan time.Time)}
+	loader, err := envhttp.PollingLoader(ctx, envhttp.Config{URL: srv.URL, Clock: clock}, time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if level := loader("LEVEL"); level != "info" {
+		t.Fatalf("unexpected initial value: %q", level)
+	}
+
+	clock.tick()
+	clock.tick()
+	if level := loader("LEVEL"); level != "info" {
+		t.Fatalf("value changed before the config did: %q", level)
+	}
+	clock.tick()
+	if level := loader("LEVEL"); level != "debug" {
+		t.Fatalf("value was not refreshed, still %q", level)
+	}
+	if notModified.Load() != 1 {
+		t.Log("expected an unchanged config to be answered with 304 Not Modified")
+		t.Fail()
+	}
+
+	if _, err := envhttp.PollingLoader(ctx, envhttp.Config{URL: srv.URL}, 0); err == nil {
+		t.Log("expected an error for a zero interval")
+		t.Fail()
+	}
+}
+
+// manualClock is an env.Clock whose ticker only fires when tick is called.
+type manualClock struct {
+	ch chan time.Time
+}
+
+func (c manualClock) Now() time.Time                     { return time.Now() }
+func (c manualClock) NewTicker(time.Duration) env.Ticker { return c }
+func (c manualClock) C() <-chan time.Time                { return c.ch }
+func (c manualClock) Stop()                              {}
+
+// tick fires the ticker, blocking until the poller receives it, i.e. until the previous poll has completed.
+func (c manualClock) tick() {
+	c.ch <- time.Now()
+}
@@ -0,0 +1,205 @@
+// Package envk8s resolves environment variables from Kubernetes ConfigMaps and Secrets read from the API server, so changes can be picked up without
+// restarting pods.
+//
+// ConfigMaps and Secrets mounted as a volume need no API access: env.DirLoader reads their keys from the mount directory, and as it reads on every
+// lookup, it sees the updates the kubelet makes to the volume.
+package envk8s
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/ndisidore/go-env"
+)
+
+// DefaultServiceAccountDir is where Kubernetes mounts the credentials of a pod's service account.
+const DefaultServiceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+
+// Config locates the ConfigMap or Secret and the API server holding it. The zero values of the connection fields use the in-cluster configuration.
+type Config struct {
+	// Name is the name of the ConfigMap or Secret.
+	Name string
+	// Namespace is the namespace of the ConfigMap or Secret, the pod's own namespace if empty.
+	Namespace string
+	// Secret reads a Secret rather than a ConfigMap.
+	Secret bool
+
+	// Host is the base URL of the API server, derived from KUBERNETES_SERVICE_HOST and KUBERNETES_SERVICE_PORT if empty.
+	Host string
+	// ServiceAccountDir holds the token, CA certificate and namespace of the service account, DefaultServiceAccountDir if empty.
+	// The token is re-read for every request, as projected tokens are rotated.
+	ServiceAccountDir string
+	// Client makes the requests, a client trusting the service account's CA certificate if nil.
+	Client *http.Client
+	// Clock schedules the polls of PollingLoader, env.SystemClock if nil.
+	Clock env.Clock
+}
+
+// object is the part of a ConfigMap or Secret holding its keys. Secret data is base64 encoded, which encoding/json decodes into a []byte.
+type object struct {
+	Data       map[string]json.RawMessage `json:"data"`
+	BinaryData map[string][]byte          `json:"binaryData"`
+}
+
+// Loader reads the ConfigMap or Secret once, returning a loader that resolves keys from that snapshot.
+func Loader(ctx context.Context, cfg Config) (env.EnvLoader, error) {
+	cfg, err := cfg.resolve()
+	if err != nil {
+		return nil, err
+	}
+	data, err := cfg.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return func(key string) string {
+		return data[key]
+	}, nil
+}
+
+// PollingLoader reads the ConfigMap or Secret, then re-reads it every interval until the context is done, so changes are picked up without a restart.
+// Failed reads are logged and leave the previous snapshot in place.
+func PollingLoader(ctx context.Context, cfg Config, interval time.Duration) (env.EnvLoader, error) {
+	if interval <= 0 {
+		return nil, errors.New("poll interval must be positive")
+	}
+	cfg, err := cfg.resolve()
+	if err != nil {
+		return nil, err
+	}
+	data, err := cfg.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var current atomic.Pointer[map[string]string]
+	current.Store(&data)
+	go func() {
+		clock := cfg.Clock
+		if clock == nil {
+			clock = env.SystemClock()
+		}
+		ticker := clock.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C():
+			}
+			next, err := cfg.get(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					slog.Default().WarnContext(ctx, "failed to refresh kubernetes object", slog.String("name", cfg.Name), slog.String("error", err.Error()))
+				}
+				continue
+			}
+			current.Store(&next)
+		}
+	}()
+
+	return func(key string) string {
+		return (*current.Load())[key]
+	}, nil
+}
+
+// resolve fills in the in-cluster defaults of cfg.
+func (cfg Config) resolve() (Config, error) {
+	if cfg.Name == "" {
+		return cfg, errors.New("name cannot be empty")
+	}
+	if cfg.ServiceAccountDir == "" {
+		cfg.ServiceAccountDir = DefaultServiceAccountDir
+	}
+	if cfg.Namespace == "" {
+		ns, err := os.ReadFile(filepath.Join(cfg.ServiceAccountDir, "namespace"))
+		if err != nil {
+			return cfg, fmt.Errorf("failed to read pod namespace: %w", err)
+		}
+		cfg.Namespace = strings.TrimSpace(string(ns))
+	}
+	if cfg.Host == "" {
+		host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+		if host == "" || port == "" {
+			return cfg, errors.New("not running in a cluster: KUBERNETES_SERVICE_HOST and KUBERNETES_SERVICE_PORT are unset")
+		}
+		cfg.Host = "https://" + net.JoinHostPort(host, port)
+	}
+	if cfg.Client == nil {
+		caCert, err := os.ReadFile(filepath.Join(cfg.ServiceAccountDir, "ca.crt"))
+		if err != nil {
+			return cfg, fmt.Errorf("failed to read cluster CA certificate: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caCert) {
+			return cfg, errors.New("cluster CA certificate contains no certificates")
+		}
+		cfg.Client = &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}}}
+	}
+	return cfg, nil
+}
+
+// get reads the keys of the ConfigMap or Secret.
+func (cfg Config) get(ctx context.Context) (map[string]string, error) {
+	kind := "configmaps"
+	if cfg.Secret {
+		kind = "secrets"
+	}
+	endpoint := strings.TrimSuffix(cfg.Host, "/") + "/api/v1/namespaces/" + url.PathEscape(cfg.Namespace) + "/" + kind + "/" + url.PathEscape(cfg.Name)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build kubernetes request: %w", err)
+	}
+	token, err := os.ReadFile(filepath.Join(cfg.ServiceAccountDir, "token"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read service account token: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := cfg.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query kubernetes: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("kubernetes returned %s for %s %s/%s", resp.Status, strings.TrimSuffix(kind, "s"), cfg.Namespace, cfg.Name)
+	}
+
+	var obj object
+	if err := json.NewDecoder(resp.Body).Decode(&obj); err != nil {
+		return nil, fmt.Errorf("failed to decode kubernetes response: %w", err)
+	}
+	data := make(map[string]string, len(obj.Data)+len(obj.BinaryData))
+	for k, v := range obj.BinaryData {
+		data[k] = string(v)
+	}
+	for k, raw := range obj.Data {
+		if cfg.Secret {
+			var v []byte
+			if err := json.Unmarshal(raw, &v); err != nil {
+				return nil, fmt.Errorf("failed to decode secret key %s: %w", k, err)
+			}
+			data[k] = string(v)
+			continue
+		}
+		var v string
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return nil, fmt.Errorf("failed to decode configmap key %s: %w", k, err)
+		}
+		data[k] = v
+	}
+	return data, nil
+}
@@ -0,0 +1,142 @@
+package envk8s_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ndisidore/go-env"
+	"github.com/ndisidore/go-env/envk8s"
+)
+
+// serviceAccount writes a service account directory with the given token and namespace.
+func serviceAccount(t *testing.T, token, namespace string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, contents := range map[string]string{"token": token, "namespace": namespace} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(contents+"\n"), 0o600); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	return dir
+}
+
+func TestLoader(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer t0ken" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/api/v1/namespaces/shop/configmaps/app":
+			_, _ = w.Write([]byte(`{"data":{"PORT":"8080"},"binaryData":{"LOGO":"aGk="}}`))
+		case "/api/v1/namespaces/other/secrets/app":
+			_, _ = w.Write([]byte(`{"data":{"DB_PASSWORD":"aHVudGVyMg=="}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	sa := serviceAccount(t, "t0ken", "shop")
+
+	loader, err := envk8s.Loader(context.Background(), envk8s.Config{Name: "app", Host: srv.URL, ServiceAccountDir: sa, Client: srv.Client()})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if port, err := env.FromEnvOrDefault(context.Background(), "PORT", 0, env.WithEnvLoader(loader)); err != nil || port != 8080 {
+		t.Logf("FromEnvOrDefault returned (%d, %v)", port, err)
+		t.Fail()
+	}
+	if logo := loader("LOGO"); logo != "hi" {
+		t.Logf("unexpected binary value: %q", logo)
+		t.Fail()
+	}
+
+	secret, err := envk8s.Loader(context.Background(), envk8s.Config{Name: "app", Namespace: "other", Secret: true, Host: srv.URL, ServiceAccountDir: sa, Client: srv.Client()})
+	if err != nil || secret("DB_PASSWORD") != "hunter2" {
+		t.Logf("unexpected secret value (%q, %v)", secret("DB_PASSWORD"), err)
+		t.Fail()
+	}
+
+	cases := []struct {
+		name                string
+		cfg                 envk8s.Config
+		expectedErrContains string
+	}{
+		{name: "no name", cfg: envk8s.Config{Host: srv.URL, ServiceAccountDir: sa}, expectedErrContains: "name cannot be empty"},
+		{name: "not found", cfg: envk8s.Config{Name: "nope", Host: srv.URL, ServiceAccountDir: sa, Client: srv.Client()}, expectedErrContains: "404 Not Found for configmap shop/nope"},
+		{name: "no namespace", cfg: envk8s.Config{Name: "app", Host: srv.URL, ServiceAccountDir: t.TempDir()}, expectedErrContains: "failed to read pod namespace"},
+		{name: "no CA", cfg: envk8s.Config{Name: "app", Host: srv.URL, ServiceAccountDir: sa}, expectedErrContains: "failed to read cluster CA certificate"},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if _, err := envk8s.Loader(context.Background(), tt.cfg); err == nil || !strings.Contains(err.Error(), tt.expectedErrContains) {
+				t.Logf("unexpected error: %v", err)
+				t.Fail()
+			}
+		})
+	}
+}
+
+func TestPollingLoader(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if calls.Add(1) == 1 {
+			_, _ = w.Write([]byte(`{"data":{"LEVEL":"info"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"LEVEL":"debug"}}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	cfg := envk8s.Config{Name: "app", Host: srv.URL, ServiceAccountDir: serviceAccount(t, "t0ken", "shop"), Client: srv.Client()}
+	clock := manualClock{ch: make(chan time.Time)}
+	cfg.Clock = clock
+	loader, err := envk8s.PollingLoader(ctx, cfg, time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if level := loader("LEVEL"); level != "info" {
+		t.Fatalf("unexpected initial value: %q", level)
+	}
+
+	// the second tick is received once the poll started by the first has completed
+	clock.tick()
+	clock.tick()
+	if level := loader("LEVEL"); level != "debug" {
+		t.Fatalf("value was not refreshed, still %q", level)
+	}
+
+	if _, err := envk8s.PollingLoader(ctx, cfg, 0); err == nil {
+		t.Log("expected an error for a zero interval")
+		t.Fail()
+	}
+}
+
+// manualClock is an env.Clock whose ticker only fires when tick is called.
+type manualClock struct {
+	ch chan time.Time
+}
+
+func (c manualClock) Now() time.Time                     { return time.Now() }
+func (c manualClock) NewTicker(time.Duration) env.Ticker { return c }
+func (c manualClock) C() <-chan time.Time                { return c.ch }
+func (c manualClock) Stop()                              {}
+
+// tick fires the ticker, blocking until the poller receives it, i.e. until the previous poll has completed.
+func (c manualClock) tick() {
+	c.ch <- time.Now()
+}
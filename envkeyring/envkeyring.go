@@ -0,0 +1,42 @@
+// Package envkeyring resolves environment variables from the OS credential store, so local development secrets need not be kept in plaintext
+// dotenv files.
+//
+// Secrets are stored under a service name with the key as the account, as by github.com/zalando/go-keyring, so either can read what the other stores:
+//   - macOS: a generic password in the login Keychain, read via the `security` tool.
+//   - Linux: a Secret Service item with `service` and `username` attributes, read via the `secret-tool` tool.
+//   - Windows: a generic credential in the Credential Manager targeting `service:key`.
+package envkeyring
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ndisidore/go-env"
+)
+
+// lookupTimeout bounds each lookup, as the credential store may block on an unlock prompt.
+const lookupTimeout = 30 * time.Second
+
+// ErrNotFound is returned by Lookup when the credential store has no secret for the key.
+var ErrNotFound = errors.New("secret not found in keyring")
+
+// Lookup reads the secret stored for key under service. It returns ErrNotFound when there is none, and an error wrapping errors.ErrUnsupported on
+// platforms without a supported credential store.
+func Lookup(ctx context.Context, service, key string) (string, error) {
+	if service == "" || key == "" {
+		return "", errors.New("service and key cannot be empty")
+	}
+	ctx, cancel := context.WithTimeout(ctx, lookupTimeout)
+	defer cancel()
+	return lookup(ctx, service, key)
+}
+
+// Loader returns a loader resolving each key from the secret stored for it under service. Keys without a secret, or that cannot be read, are unset.
+// The store is consulted on every lookup, so the loader is best chained behind faster sources or wrapped in a cache.
+func Loader(service string) env.EnvLoader {
+	return func(key string) string {
+		val, _ := Lookup(context.Background(), service, key)
+		return val
+	}
+}
@@ -0,0 +1,25 @@
+package envkeyring
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// itemNotFound is the exit code of `security` when there is no matching item.
+const itemNotFound = 44
+
+// lookup reads a generic password from the Keychain.
+func lookup(ctx context.Context, service, key string) (string, error) {
+	out, err := exec.CommandContext(ctx, "security", "find-generic-password", "-s", service, "-a", key, "-w").Output()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == itemNotFound {
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read keychain: %w", err)
+	}
+	return strings.TrimSuffix(string(out), "\n"), nil
+}
@@ -0,0 +1,25 @@
+package envkeyring
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+)
+
+// lookup reads a Secret Service item. secret-tool exits with 1 and no output when there is no matching item.
+func lookup(ctx context.Context, service, key string) (string, error) {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "secret-tool", "lookup", "service", service, "username", key)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 && len(out) == 0 && stderr.Len() == 0 {
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret service: %w", err)
+	}
+	return string(out), nil
+}
@@ -0,0 +1,58 @@
+package envkeyring_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ndisidore/go-env"
+	"github.com/ndisidore/go-env/envkeyring"
+)
+
+// fakeSecretTool puts a secret-tool on the PATH that only knows the API_TOKEN of the app service.
+func fakeSecretTool(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	script := `#!/bin/sh
+[ "$1 $2 $3 $4 $5" = "lookup service app username API_TOKEN" ] && printf 's3cret' && exit 0
+[ "$5" = "BROKEN" ] && echo "no secret service" >&2
+exit 1
+`
+	if err := os.WriteFile(filepath.Join(dir, "secret-tool"), []byte(script), 0o700); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestLookup(t *testing.T) {
+	fakeSecretTool(t)
+
+	if val, err := envkeyring.Lookup(context.Background(), "app", "API_TOKEN"); err != nil || val != "s3cret" {
+		t.Logf("Lookup returned (%q, %v)", val, err)
+		t.Fail()
+	}
+	if _, err := envkeyring.Lookup(context.Background(), "app", "OTHER"); !errors.Is(err, envkeyring.ErrNotFound) {
+		t.Logf("expected ErrNotFound, got %v", err)
+		t.Fail()
+	}
+	if _, err := envkeyring.Lookup(context.Background(), "app", "BROKEN"); err == nil || errors.Is(err, envkeyring.ErrNotFound) {
+		t.Logf("expected a read failure, got %v", err)
+		t.Fail()
+	}
+	if _, err := envkeyring.Lookup(context.Background(), "", "API_TOKEN"); err == nil {
+		t.Log("expected an error for an empty service")
+		t.Fail()
+	}
+
+	token, err := env.FromEnvOrDefault(context.Background(), "API_TOKEN", "", env.WithEnvLoader(envkeyring.Loader("app")))
+	if err != nil || token != "s3cret" {
+		t.Logf("FromEnvOrDefault returned (%q, %v)", token, err)
+		t.Fail()
+	}
+	if missing := envkeyring.Loader("app")("OTHER"); missing != "" {
+		t.Logf("expected an unset value, got %q", missing)
+		t.Fail()
+	}
+}
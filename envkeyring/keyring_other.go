@@ -0,0 +1,15 @@
+//go:build !darwin && !linux && !windows
+
+package envkeyring
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+)
+
+// lookup reports that there is no supported credential store.
+func lookup(context.Context, string, string) (string, error) {
+	return "", fmt.Errorf("no supported credential store on %s: %w", runtime.GOOS, errors.ErrUnsupported)
+}
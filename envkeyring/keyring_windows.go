@@ -0,0 +1,61 @@
+package envkeyring
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	advapi32      = syscall.NewLazyDLL("advapi32.dll")
+	procCredReadW = advapi32.NewProc("CredReadW")
+	procCredFree  = advapi32.NewProc("CredFree")
+)
+
+const (
+	// credTypeGeneric is CRED_TYPE_GENERIC.
+	credTypeGeneric = 1
+	// errorNotFound is ERROR_NOT_FOUND.
+	errorNotFound syscall.Errno = 1168
+)
+
+// credential mirrors the CREDENTIALW struct.
+type credential struct {
+	Flags              uint32
+	Type               uint32
+	TargetName         *uint16
+	Comment            *uint16
+	LastWritten        syscall.Filetime
+	CredentialBlobSize uint32
+	CredentialBlob     *byte
+	Persist            uint32
+	AttributeCount     uint32
+	Attributes         uintptr
+	TargetAlias        *uint16
+	UserName           *uint16
+}
+
+// lookup reads a generic credential from the Credential Manager.
+func lookup(_ context.Context, service, key string) (string, error) {
+	target, err := syscall.UTF16PtrFromString(service + ":" + key)
+	if err != nil {
+		return "", fmt.Errorf("invalid credential target: %w", err)
+	}
+
+	var cred *credential
+	ret, _, callErr := procCredReadW.Call(uintptr(unsafe.Pointer(target)), credTypeGeneric, 0, uintptr(unsafe.Pointer(&cred)))
+	if ret == 0 {
+		if errors.Is(callErr, errorNotFound) {
+			return "", ErrNotFound
+		}
+		return "", fmt.Errorf("failed to read credential manager: %w", callErr)
+	}
+	defer procCredFree.Call(uintptr(unsafe.Pointer(cred)))
+
+	if cred.CredentialBlobSize == 0 {
+		return "", nil
+	}
+	return string(unsafe.Slice(cred.CredentialBlob, cred.CredentialBlobSize)), nil
+}
@@ -0,0 +1,89 @@
+//go:build !tinygo
+
+// Package envlog keeps a slog level in sync with an environment variable.
+package envlog
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ndisidore/go-env"
+)
+
+// DynamicLevel returns a LevelVar initialized from the env var and re-synced every interval until the context is done.
+//
+// Values are parsed as by env.ParseLevel, e.g. `debug`, `INFO`, `warn+2` or `-4`. Invalid values are logged and ignored.
+func DynamicLevel(ctx context.Context, envVar string, interval time.Duration, opts ...env.EnvParseOption) (*slog.LevelVar, error) {
+	pollCtx, cancel := context.WithCancel(ctx)
+	ch, err := env.Poll(pollCtx, envVar, "", interval, opts...)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	lv := new(slog.LevelVar)
+	if err := setLevel(lv, <-ch); err != nil {
+		// stop the poll, which would otherwise run until ctx is done with nobody receiving
+		cancel()
+		return nil, fmt.Errorf("failed to parse env %s to slog.Level: %w", envVar, err)
+	}
+	go func() {
+		defer cancel()
+		for raw := range ch {
+			if err := setLevel(lv, raw); err != nil {
+				slog.Default().WarnContext(ctx, "ignoring invalid log level", slog.String("env_var", envVar), slog.String("error", err.Error()))
+			}
+		}
+	}()
+
+	return lv, nil
+}
+
+// Handler exposes the level over HTTP. GET reports the current level and PUT or POST sets it from the request body.
+//
+// A level set over HTTP is retained until the env var next changes.
+func Handler(lv *slog.LevelVar) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut, http.MethodPost:
+			body, err := io.ReadAll(io.LimitReader(r.Body, 64))
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if err := setLevel(lv, string(body)); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		default:
+			w.Header().Set("Allow", "GET, PUT, POST")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		_, _ = fmt.Fprintln(w, lv.Level())
+	})
+}
+
+// setLevel parses raw into the LevelVar. An empty value resets the level to INFO.
+func setLevel(lv *slog.LevelVar, raw string) error {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		lv.Set(slog.LevelInfo)
+		return nil
+	}
+
+	level, err := env.ParseLevel(raw)
+	if err != nil {
+		return err
+	}
+	lv.Set(level)
+	return nil
+}
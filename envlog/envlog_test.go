@@ -0,0 +1,116 @@
+//go:build !tinygo
+
+package envlog_test
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ndisidore/go-env"
+	"github.com/ndisidore/go-env/envlog"
+)
+
+func TestDynamicLevel(t *testing.T) {
+	t.Parallel()
+
+	var val atomic.Value
+	val.Store("debug")
+	loader := func(key string) string {
+		return val.Load().(string)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	lv, err := envlog.DynamicLevel(ctx, "LOG_LEVEL", time.Millisecond, env.WithEnvLoader(loader))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if lv.Level() != slog.LevelDebug {
+		t.Logf("initial level (%v) does not match expected (DEBUG)", lv.Level())
+		t.Fail()
+	}
+
+	val.Store("warn")
+	deadline := time.Now().Add(time.Second)
+	for lv.Level() != slog.LevelWarn && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if lv.Level() != slog.LevelWarn {
+		t.Logf("updated level (%v) does not match expected (WARN)", lv.Level())
+		t.Fail()
+	}
+
+	// numeric levels are accepted as by the parser
+	val.Store("-4")
+	deadline = time.Now().Add(time.Second)
+	for lv.Level() != slog.LevelDebug && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if lv.Level() != slog.LevelDebug {
+		t.Logf("numeric level (%v) does not match expected (DEBUG)", lv.Level())
+		t.Fail()
+	}
+}
+
+func TestDynamicLevelInvalid(t *testing.T) {
+	t.Parallel()
+
+	var lookups atomic.Int32
+	loader := func(key string) string {
+		lookups.Add(1)
+		return "loud"
+	}
+	if _, err := envlog.DynamicLevel(context.Background(), "LOG_LEVEL", time.Millisecond, env.WithEnvLoader(loader)); err == nil {
+		t.Fatal("expected an error for an invalid initial level")
+	}
+
+	// the poll is stopped once the initial value is rejected, so the loader is no longer consulted
+	time.Sleep(20 * time.Millisecond)
+	before := lookups.Load()
+	time.Sleep(20 * time.Millisecond)
+	if after := lookups.Load(); after != before {
+		t.Logf("env var still polled after DynamicLevel failed (%d then %d lookups)", before, after)
+		t.Fail()
+	}
+}
+
+func TestHandler(t *testing.T) {
+	t.Parallel()
+
+	lv := new(slog.LevelVar)
+	h := envlog.Handler(lv)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/", strings.NewReader("error")))
+	if rec.Code != http.StatusOK || lv.Level() != slog.LevelError {
+		t.Logf("unexpected response (%d) or level (%v)", rec.Code, lv.Level())
+		t.Fail()
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/", strings.NewReader("loud")))
+	if rec.Code != http.StatusBadRequest {
+		t.Logf("unexpected response (%d) for an invalid level", rec.Code)
+		t.Fail()
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/", strings.NewReader("8")))
+	if rec.Code != http.StatusOK || lv.Level() != slog.LevelError {
+		t.Logf("unexpected response (%d) or level (%v) for a numeric level", rec.Code, lv.Level())
+		t.Fail()
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if strings.TrimSpace(rec.Body.String()) != "ERROR" {
+		t.Logf("unexpected body (%s)", rec.Body.String())
+		t.Fail()
+	}
+}
@@ -0,0 +1,79 @@
+package envruntime
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// DefaultCgroupRoot is where the cgroup filesystem is conventionally mounted.
+const DefaultCgroupRoot = "/sys/fs/cgroup"
+
+// unlimitedV1 is the threshold above which a cgroup v1 memory limit is treated as unlimited, as v1 reports no limit as a page-aligned maximum int64.
+const unlimitedV1 = 1 << 62
+
+// CgroupMemoryLimit reads the memory limit, in bytes, of the cgroup mounted at root, supporting both cgroup v2 (`memory.max`) and v1 (`memory/memory.limit_in_bytes`).
+// The second return is false when there is no cgroup or it has no limit.
+func CgroupMemoryLimit(root string) (int64, bool, error) {
+	raw, err := readCgroupFile(filepath.Join(root, "memory.max"), filepath.Join(root, "memory", "memory.limit_in_bytes"))
+	if err != nil || raw == "" || raw == "max" {
+		return 0, false, err
+	}
+
+	limit, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid cgroup memory limit %q: %w", raw, err)
+	}
+	if limit >= unlimitedV1 {
+		return 0, false, nil
+	}
+	return limit, true, nil
+}
+
+// CgroupCPUQuota reads the CPU quota, in CPUs, of the cgroup mounted at root, supporting both cgroup v2 (`cpu.max`) and v1 (`cpu/cpu.cfs_quota_us`).
+// The second return is false when there is no cgroup or it has no quota.
+func CgroupCPUQuota(root string) (float64, bool, error) {
+	raw, err := readCgroupFile(filepath.Join(root, "cpu.max"))
+	if err != nil {
+		return 0, false, err
+	}
+	quota, period, _ := strings.Cut(raw, " ")
+	if raw == "" {
+		// cgroup v1 splits the quota and period across files
+		if quota, err = readCgroupFile(filepath.Join(root, "cpu", "cpu.cfs_quota_us")); err != nil {
+			return 0, false, err
+		}
+		if period, err = readCgroupFile(filepath.Join(root, "cpu", "cpu.cfs_period_us")); err != nil {
+			return 0, false, err
+		}
+	}
+	if quota == "" || quota == "max" || quota == "-1" {
+		return 0, false, nil
+	}
+
+	q, qErr := strconv.ParseFloat(quota, 64)
+	p, pErr := strconv.ParseFloat(period, 64)
+	if qErr != nil || pErr != nil || q <= 0 || p <= 0 {
+		return 0, false, fmt.Errorf("invalid cgroup CPU quota %q over period %q", quota, period)
+	}
+	return q / p, true, nil
+}
+
+// readCgroupFile returns the trimmed contents of the first of paths that exists, or an empty string if none do.
+func readCgroupFile(paths ...string) (string, error) {
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to read cgroup file: %w", err)
+		}
+		return strings.TrimSpace(string(data)), nil
+	}
+	return "", nil
+}
@@ -0,0 +1,47 @@
+package envruntime
+
+import (
+	"errors"
+	"math"
+	"runtime"
+)
+
+// Workers returns a func, for use with env.WithDefaultFunc, computing perCPU workers for each CPU available to the process: the CPU quota of the cgroup
+// mounted at root (DefaultCgroupRoot if empty), capped at runtime.NumCPU. The count is rounded up and at least one.
+func Workers(root string, perCPU float64) func() (int, error) {
+	if root == "" {
+		root = DefaultCgroupRoot
+	}
+	return func() (int, error) {
+		if perCPU <= 0 {
+			return 0, errors.New("workers per CPU must be positive")
+		}
+		cpus := float64(runtime.NumCPU())
+		quota, ok, err := CgroupCPUQuota(root)
+		if err != nil {
+			return 0, err
+		}
+		if ok {
+			cpus = min(cpus, quota)
+		}
+		return max(1, int(math.Ceil(cpus*perCPU))), nil
+	}
+}
+
+// MemoryFraction returns a func, for use with env.WithDefaultFunc, computing the given fraction of the memory limit of the cgroup mounted at root
+// (DefaultCgroupRoot if empty) in bytes, e.g. to size a cache, or fallback when there is no limit.
+func MemoryFraction(root string, fraction float64, fallback int64) func() (int64, error) {
+	if root == "" {
+		root = DefaultCgroupRoot
+	}
+	return func() (int64, error) {
+		if fraction <= 0 || fraction > 1 {
+			return 0, errors.New("memory fraction must be in (0, 1]")
+		}
+		limit, ok, err := CgroupMemoryLimit(root)
+		if err != nil || !ok {
+			return fallback, err
+		}
+		return int64(float64(limit) * fraction), nil
+	}
+}
@@ -0,0 +1,65 @@
+package envruntime_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/ndisidore/go-env"
+	"github.com/ndisidore/go-env/envruntime"
+)
+
+func TestDefaults(t *testing.T) {
+	t.Parallel()
+
+	v2, v1, none := t.TempDir(), t.TempDir(), t.TempDir()
+	files := map[string]string{
+		filepath.Join(v2, "cpu.max"):                         "50000 100000\n",
+		filepath.Join(v2, "memory.max"):                      "1073741824\n",
+		filepath.Join(v1, "cpu", "cpu.cfs_quota_us"):         "-1\n",
+		filepath.Join(v1, "cpu", "cpu.cfs_period_us"):        "100000\n",
+		filepath.Join(v1, "memory", "memory.limit_in_bytes"): "536870912\n",
+	}
+	for path, contents := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	unset := env.WithEnvLoader(func(string) string { return "" })
+
+	cases := []struct {
+		name     string
+		root     string
+		workers  int
+		cacheMiB int64
+	}{
+		{name: "cgroup v2", root: v2, workers: 2, cacheMiB: 256},
+		{name: "cgroup v1 without quota", root: v1, workers: 4 * runtime.NumCPU(), cacheMiB: 128},
+		{name: "no cgroup", root: none, workers: 4 * runtime.NumCPU(), cacheMiB: 64},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			workers, err := env.FromEnvOrDefault(context.Background(), "WORKERS", 1, unset, env.WithDefaultFunc(envruntime.Workers(tt.root, 4)))
+			if err != nil || workers != tt.workers {
+				t.Logf("workers returned (%d, %v), expected %d", workers, err, tt.workers)
+				t.Fail()
+			}
+			cache, err := env.FromEnvOrDefault(context.Background(), "CACHE_BYTES", int64(0), unset, env.WithDefaultFunc(envruntime.MemoryFraction(tt.root, 0.25, 64<<20)))
+			if err != nil || cache != tt.cacheMiB<<20 {
+				t.Logf("cache size returned (%d, %v), expected %d", cache, err, tt.cacheMiB<<20)
+				t.Fail()
+			}
+		})
+	}
+
+	if _, err := env.FromEnvOrDefault(context.Background(), "WORKERS", 1, unset, env.WithDefaultFunc(envruntime.Workers(none, 0))); err == nil {
+		t.Log("expected an error for a non-positive worker multiplier")
+		t.Fail()
+	}
+}
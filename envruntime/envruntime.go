@@ -0,0 +1,156 @@
+// Package envruntime tunes the Go runtime from environment variables, taking the limits of the enclosing cgroup into account.
+package envruntime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"runtime/debug"
+	"strconv"
+	"strings"
+
+	"github.com/ndisidore/go-env"
+)
+
+type (
+	// Config names the app-specific env vars consulted by Tune, which take precedence over the standard GOMEMLIMIT and GOGC.
+	// Empty keys are not consulted.
+	Config struct {
+		// MemoryLimitKey accepts the GOMEMLIMIT syntax, e.g. `512MiB` or `off`, or a percentage of the cgroup memory limit, e.g. `90%`.
+		MemoryLimitKey string
+		// GCPercentKey accepts the GOGC syntax, e.g. `200` or `off`.
+		GCPercentKey string
+		// CgroupRoot is where the cgroup filesystem is mounted, DefaultCgroupRoot if empty.
+		CgroupRoot string
+	}
+
+	// Tuning reports the runtime settings in effect after Tune.
+	Tuning struct {
+		// MemoryLimit is the soft memory limit in bytes, math.MaxInt64 when there is none.
+		MemoryLimit int64
+		// GCPercent is the GC target percentage, -1 when the GC is off.
+		GCPercent int
+	}
+)
+
+// Tune applies the memory limit and GC percentage configured by the app-specific keys, falling back to GOMEMLIMIT and GOGC, via debug.SetMemoryLimit and
+// debug.SetGCPercent. Settings that are not configured are left as they are. The options are used to load each key.
+//
+// The runtime applies GOMEMLIMIT and GOGC itself at startup, but only Tune accepts a memory limit relative to the cgroup limit.
+func Tune(ctx context.Context, cfg Config, opts ...env.EnvParseOption) (Tuning, error) {
+	if cfg.CgroupRoot == "" {
+		cfg.CgroupRoot = DefaultCgroupRoot
+	}
+
+	memKey, rawMem, err := firstSet(ctx, opts, cfg.MemoryLimitKey, "GOMEMLIMIT")
+	if err != nil {
+		return Tuning{}, err
+	}
+	gcKey, rawGC, err := firstSet(ctx, opts, cfg.GCPercentKey, "GOGC")
+	if err != nil {
+		return Tuning{}, err
+	}
+
+	var (
+		memLimit  int64
+		gcPercent int
+	)
+	if rawMem != "" {
+		if memLimit, err = parseMemoryLimit(rawMem, cfg.CgroupRoot); err != nil {
+			return Tuning{}, &env.ParseError{EnvVar: memKey, Type: "memory limit", Err: err}
+		}
+	}
+	if rawGC != "" {
+		if gcPercent, err = parseGCPercent(rawGC); err != nil {
+			return Tuning{}, &env.ParseError{EnvVar: gcKey, Type: "GC percent", Err: err}
+		}
+	}
+
+	// both settings are validated before either is applied
+	if rawMem != "" {
+		debug.SetMemoryLimit(memLimit)
+	}
+	if rawGC != "" {
+		debug.SetGCPercent(gcPercent)
+	}
+	return Tuning{MemoryLimit: debug.SetMemoryLimit(-1), GCPercent: currentGCPercent()}, nil
+}
+
+// firstSet returns the first of keys with a value, skipping empty keys.
+func firstSet(ctx context.Context, opts []env.EnvParseOption, keys ...string) (string, string, error) {
+	for _, key := range keys {
+		if key == "" {
+			continue
+		}
+		val, err := env.FromEnvOrDefault(ctx, key, "", opts...)
+		if err != nil || val != "" {
+			return key, val, err
+		}
+	}
+	return "", "", nil
+}
+
+// byteSuffixes maps the unit suffixes accepted by GOMEMLIMIT to their multipliers, longest first so `B` does not shadow `KiB`.
+var byteSuffixes = []struct {
+	suffix string
+	mult   int64
+}{
+	{"TiB", 1 << 40}, {"GiB", 1 << 30}, {"MiB", 1 << 20}, {"KiB", 1 << 10}, {"B", 1},
+}
+
+// parseMemoryLimit parses a GOMEMLIMIT-style byte count, `off`, or a percentage of the cgroup memory limit.
+func parseMemoryLimit(raw, cgroupRoot string) (int64, error) {
+	if raw == "off" {
+		return math.MaxInt64, nil
+	}
+	if pct, ok := strings.CutSuffix(raw, "%"); ok {
+		p, err := strconv.ParseFloat(pct, 64)
+		if err != nil || p <= 0 || p > 100 {
+			return 0, fmt.Errorf("invalid percentage %q (want a number in (0, 100])", raw)
+		}
+		limit, ok, err := CgroupMemoryLimit(cgroupRoot)
+		if err != nil {
+			return 0, err
+		}
+		if !ok {
+			return 0, errors.New("a percentage requires a cgroup memory limit, but none was found")
+		}
+		return int64(float64(limit) * p / 100), nil
+	}
+
+	num, mult := raw, int64(1)
+	for _, s := range byteSuffixes {
+		if n, ok := strings.CutSuffix(raw, s.suffix); ok {
+			num, mult = n, s.mult
+			break
+		}
+	}
+	n, err := strconv.ParseInt(num, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid byte count %q (want e.g. 512MiB, 90%% or off)", raw)
+	}
+	if n > math.MaxInt64/mult {
+		return 0, fmt.Errorf("byte count %q overflows", raw)
+	}
+	return n * mult, nil
+}
+
+// parseGCPercent parses a GOGC-style percentage or `off`, which disables the GC.
+func parseGCPercent(raw string) (int, error) {
+	if raw == "off" {
+		return -1, nil
+	}
+	p, err := strconv.Atoi(raw)
+	if err != nil || p < 0 {
+		return 0, fmt.Errorf("invalid GC percent %q (want a non-negative integer or off)", raw)
+	}
+	return p, nil
+}
+
+// currentGCPercent reads the GC percentage, which the runtime only exposes by setting it.
+func currentGCPercent() int {
+	p := debug.SetGCPercent(100)
+	debug.SetGCPercent(p)
+	return p
+}
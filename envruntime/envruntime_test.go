@@ -0,0 +1,76 @@
+package envruntime_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+	"testing"
+
+	"github.com/ndisidore/go-env"
+	"github.com/ndisidore/go-env/envruntime"
+)
+
+// TestTune is not parallel as it changes process-wide runtime settings, which are restored once it completes.
+func TestTune(t *testing.T) {
+	prevLimit, prevGC := debug.SetMemoryLimit(-1), debug.SetGCPercent(100)
+	debug.SetGCPercent(prevGC)
+	t.Cleanup(func() {
+		debug.SetMemoryLimit(prevLimit)
+		debug.SetGCPercent(prevGC)
+	})
+
+	v2, v1, none := t.TempDir(), t.TempDir(), t.TempDir()
+	if err := os.WriteFile(filepath.Join(v2, "memory.max"), []byte("1073741824\n"), 0o600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(v1, "memory"), 0o700); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(v1, "memory", "memory.limit_in_bytes"), []byte("9223372036854771712\n"), 0o600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cfg := envruntime.Config{MemoryLimitKey: "APP_MEMORY_LIMIT", GCPercentKey: "APP_GC_PERCENT"}
+	cases := []struct {
+		name                string
+		vals                map[string]string
+		cgroupRoot          string
+		expected            envruntime.Tuning
+		expectedErrContains string
+	}{
+		{name: "app overrides", vals: map[string]string{"APP_MEMORY_LIMIT": "90%", "GOMEMLIMIT": "1GiB", "APP_GC_PERCENT": "off", "GOGC": "50"}, cgroupRoot: v2, expected: envruntime.Tuning{MemoryLimit: 966367641, GCPercent: -1}},
+		{name: "standard knobs", vals: map[string]string{"GOMEMLIMIT": "512MiB", "GOGC": "200"}, cgroupRoot: none, expected: envruntime.Tuning{MemoryLimit: 512 << 20, GCPercent: 200}},
+		{name: "off", vals: map[string]string{"APP_MEMORY_LIMIT": "off"}, cgroupRoot: none, expected: envruntime.Tuning{MemoryLimit: math.MaxInt64, GCPercent: 200}},
+		{name: "unlimited cgroup v1", vals: map[string]string{"APP_MEMORY_LIMIT": "50%"}, cgroupRoot: v1, expectedErrContains: "requires a cgroup memory limit"},
+		{name: "bad percent", vals: map[string]string{"APP_MEMORY_LIMIT": "150%"}, cgroupRoot: v2, expectedErrContains: "invalid percentage"},
+		{name: "bad bytes", vals: map[string]string{"GOMEMLIMIT": "lots"}, cgroupRoot: v2, expectedErrContains: "GOMEMLIMIT"},
+		{name: "bad gc", vals: map[string]string{"APP_MEMORY_LIMIT": "1GiB", "APP_GC_PERCENT": "-5"}, cgroupRoot: v2, expectedErrContains: "invalid GC percent"},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg.CgroupRoot = tt.cgroupRoot
+			loader := func(key string) string { return tt.vals[key] }
+			ret, err := envruntime.Tune(context.Background(), cfg, env.WithEnvLoader(loader))
+			switch {
+			case err != nil && tt.expectedErrContains != "":
+				if !strings.Contains(err.Error(), tt.expectedErrContains) || !errors.As(err, new(*env.ParseError)) {
+					t.Logf("unexpected error: %v", err)
+					t.Fail()
+				}
+			case err != nil:
+				t.Logf("unexpected error: %v", err)
+				t.Fail()
+			case tt.expectedErrContains != "":
+				t.Logf("expected error containing %q", tt.expectedErrContains)
+				t.Fail()
+			case ret != tt.expected:
+				t.Logf("return value (%+v) does not match expected (%+v)", ret, tt.expected)
+				t.Fail()
+			}
+		})
+	}
+}
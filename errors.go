@@ -0,0 +1,175 @@
+package env
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type (
+	// ParseError is returned when the value of an env var cannot be parsed into the destination type.
+	ParseError struct {
+		EnvVar string
+		Type   string
+		Err    error
+		// Hint is the remediation hint provided via WithHint, if any.
+		Hint string
+
+		msg string
+	}
+
+	// MissingError is returned when a required env var is unset, distinguishing a forgotten value from an intentional default.
+	MissingError struct {
+		EnvVar string
+		// Hint is the remediation hint provided via WithHint, if any.
+		Hint string
+
+		msg string
+	}
+
+	// TruncatedError ends the Errors returned when evaluation stopped at the limit set by WithMaxErrors, counting the fields that were not evaluated.
+	TruncatedError struct {
+		Limit   int
+		Skipped int
+	}
+
+	// OverflowError is wrapped in the ParseError returned when an integer value does not fit the destination type, naming the bound it exceeded.
+	// It unwraps to strconv.ErrRange.
+	OverflowError struct {
+		Value string
+		Type  string
+		// Limit is the max, or the min if Below is set, value the type allows.
+		Limit string
+		Below bool
+	}
+
+	// ExpansionLimitError is wrapped in the ParseError returned when expanding the references of Key exceeds the limit set by WithMaxExpansionDepth,
+	// or by WithMaxExpansionLength if Length is set.
+	ExpansionLimitError struct {
+		Key    string
+		Limit  int
+		Length bool
+	}
+
+	// Errors aggregates multiple errors, e.g. from parsing many env vars, while keeping each one inspectable via errors.Is/As.
+	Errors []error
+)
+
+func (e *ParseError) Error() string {
+	msg := e.msg
+	if msg == "" {
+		msg = fmt.Sprintf(defaultErrorMessages[MsgParseFailed], e.EnvVar, e.Type, e.Err)
+	}
+	return withHint(msg, e.Hint)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+func (e *MissingError) Error() string {
+	msg := e.msg
+	if msg == "" {
+		msg = fmt.Sprintf(defaultErrorMessages[MsgRequired], e.EnvVar)
+	}
+	return withHint(msg, e.Hint)
+}
+
+func (e *TruncatedError) Error() string {
+	return fmt.Sprintf("stopped after %d errors: %d more fields not evaluated", e.Limit, e.Skipped)
+}
+
+func (e *OverflowError) Error() string {
+	bound := "max"
+	if e.Below {
+		bound = "min"
+	}
+	return fmt.Sprintf("value %s is out of range for %s (%s %s)", e.Value, e.Type, bound, e.Limit)
+}
+
+func (e *OverflowError) Unwrap() error {
+	return strconv.ErrRange
+}
+
+func (e *ExpansionLimitError) Error() string {
+	if e.Length {
+		return fmt.Sprintf("expanding %s exceeds the max length of %d bytes", e.Key, e.Limit)
+	}
+	return fmt.Sprintf("expanding %s exceeds the max reference depth of %d", e.Key, e.Limit)
+}
+
+// withHint appends a remediation hint to msg, if there is one.
+func withHint(msg, hint string) string {
+	if hint == "" {
+		return msg
+	}
+	return msg + " (hint: " + hint + ")"
+}
+
+// Append adds err to the collection, ignoring nil errors. Nested Errors are flattened.
+func (errs *Errors) Append(err error) {
+	var nested Errors
+	switch {
+	case err == nil:
+	case errors.As(err, &nested):
+		*errs = append(*errs, nested...)
+	default:
+		*errs = append(*errs, err)
+	}
+}
+
+// ErrOrNil returns the collection as an error, or nil if it is empty.
+func (errs Errors) ErrOrNil() error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+func (errs Errors) Error() string {
+	msgs := make([]string, 0, len(errs))
+	for _, err := range errs {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (errs Errors) Unwrap() []error {
+	return errs
+}
+
+// JSON renders the collection as a JSON array so tooling can consume failures without parsing messages.
+// Each element carries the error message and, where known, the env var, destination type and remediation hint.
+func (errs Errors) JSON() ([]byte, error) {
+	records := make([]errorRecord, 0, len(errs))
+	for _, err := range errs {
+		records = append(records, newErrorRecord(err))
+	}
+	return json.Marshal(records)
+}
+
+// errorRecord is the machine-readable form of a single error.
+type errorRecord struct {
+	EnvVar string `json:"env_var,omitempty"`
+	Type   string `json:"type,omitempty"`
+	Error  string `json:"error"`
+	Hint   string `json:"hint,omitempty"`
+}
+
+// newErrorRecord describes err, extracting the env var, type and hint where known.
+func newErrorRecord(err error) errorRecord {
+	rec := errorRecord{Error: err.Error()}
+	var (
+		pe *ParseError
+		me *MissingError
+	)
+	switch {
+	case errors.As(err, &pe):
+		rec.EnvVar, rec.Type, rec.Hint = pe.EnvVar, pe.Type, pe.Hint
+	case errors.As(err, &me):
+		rec.EnvVar, rec.Hint = me.EnvVar, me.Hint
+	}
+	return rec
+}
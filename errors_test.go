@@ -0,0 +1,91 @@
+package env_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/ndisidore/go-env"
+)
+
+func TestErrors(t *testing.T) {
+	t.Parallel()
+
+	loader := func(key string) string {
+		return map[string]string{"NOT_INT": "abcd", "NOT_BOOL": "abcd"}[key]
+	}
+
+	var errs env.Errors
+	_, err := env.FromEnvOrDefault(context.Background(), "NOT_INT", 0, env.WithEnvLoader(loader))
+	errs.Append(err)
+	_, err = env.FromEnvOrDefault(context.Background(), "UNKNOWN_ENV", 0, env.WithEnvLoader(loader))
+	errs.Append(err)
+	_, err = env.FromEnvOrDefault(context.Background(), "NOT_BOOL", false, env.WithEnvLoader(loader))
+	errs.Append(env.Errors{err, errors.New("other")})
+
+	if len(errs) != 3 {
+		t.Fatalf("collected %d errors, expected 3", len(errs))
+	}
+
+	var pe *env.ParseError
+	if err := errs.ErrOrNil(); !errors.As(err, &pe) || pe.EnvVar != "NOT_INT" || !errors.Is(err, strconv.ErrSyntax) {
+		t.Logf("unexpected error: %v", err)
+		t.Fail()
+	}
+
+	raw, err := errs.JSON()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var records []map[string]string
+	if err := json.Unmarshal(raw, &records); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(records) != 3 || records[0]["env_var"] != "NOT_INT" || records[1]["type"] != "bool" || records[2]["error"] != "other" {
+		t.Logf("unexpected records: %s", raw)
+		t.Fail()
+	}
+
+	if (env.Errors{}).ErrOrNil() != nil {
+		t.Log("expected an empty collection to be nil")
+		t.Fail()
+	}
+}
+
+func TestWithHint(t *testing.T) {
+	t.Parallel()
+
+	loader := func(key string) string {
+		return map[string]string{"NOT_INT": "abcd"}[key]
+	}
+	const hint = "set to a value from `vault kv list secret/app`"
+
+	var errs env.Errors
+	_, err := env.FromEnvOrDefault(context.Background(), "NOT_INT", 0, env.WithEnvLoader(loader), env.WithHint(hint))
+	errs.Append(err)
+	_, err = env.FromEnvOrDefault(context.Background(), "UNSET", 0, env.WithEnvLoader(loader), env.WithRequired(), env.WithHint(hint))
+	errs.Append(err)
+
+	for _, err := range errs {
+		if !strings.HasSuffix(err.Error(), "(hint: "+hint+")") {
+			t.Logf("error (%v) does not carry the hint", err)
+			t.Fail()
+		}
+	}
+
+	raw, err := errs.JSON()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var records []map[string]string
+	if err := json.Unmarshal(raw, &records); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(records) != 2 || records[0]["hint"] != hint || records[1]["hint"] != hint {
+		t.Logf("unexpected records: %s", raw)
+		t.Fail()
+	}
+}
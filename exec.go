@@ -0,0 +1,71 @@
+//go:build !tinygo
+
+package env
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// waitDelay is how long a command's output is waited for once it exits or is killed.
+const waitDelay = 100 * time.Millisecond
+
+// ExecLoader returns a loader resolving each key in commands by running its command and using the output, with a single trailing newline trimmed,
+// e.g. `{"DB_PASSWORD": {"op", "read", "op://prod/db/password"}}` for 1Password or `{"API_TOKEN": {"pass", "show", "api/token"}}` for pass.
+//
+// Commands are run directly rather than via a shell, and only the keys in commands are resolved: any other key is unset without running anything.
+// Each run is bounded by timeout. A command that fails or times out leaves its key unset and is logged along with its stderr. Commands are run on every
+// lookup, so the loader is best wrapped in a cache.
+func ExecLoader(commands map[string][]string, timeout time.Duration) (EnvLoader, error) {
+	if timeout <= 0 {
+		return nil, errors.New("exec timeout must be positive")
+	}
+	allowed := make(map[string][]string, len(commands))
+	for key, argv := range commands {
+		if len(argv) == 0 || argv[0] == "" {
+			return nil, fmt.Errorf("command for %s cannot be empty", key)
+		}
+		allowed[key] = append([]string(nil), argv...)
+	}
+
+	return func(key string) string {
+		argv, ok := allowed[key]
+		if !ok {
+			return ""
+		}
+		val, err := runCommand(argv, timeout)
+		if err != nil {
+			slog.Default().Warn("failed to resolve env var via command", slog.String("env_var", key), slog.String("command", argv[0]), slog.String("error", err.Error()))
+			return ""
+		}
+		return val
+	}, nil
+}
+
+// runCommand runs argv, bounded by timeout, returning its stdout with a single trailing newline trimmed.
+func runCommand(argv []string, timeout time.Duration) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	// a killed command's children may hold its output open, so stop waiting for them shortly after
+	cmd.WaitDelay = waitDelay
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return "", fmt.Errorf("timed out after %v", timeout)
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%w: %s", err, msg)
+		}
+		return "", err
+	}
+	return trimNewline(stdout.String()), nil
+}
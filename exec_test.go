@@ -0,0 +1,48 @@
+//go:build !tinygo
+
+package env_test
+
+import (
+	"context"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/ndisidore/go-env"
+)
+
+func TestExecLoader(t *testing.T) {
+	t.Parallel()
+
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not available")
+	}
+	loader, err := env.ExecLoader(map[string][]string{
+		"DB_PASSWORD": {"sh", "-c", "echo hunter2"},
+		"FAILING":     {"sh", "-c", "echo nope >&2; exit 1"},
+		"SLOW":        {"sh", "-c", "sleep 5; echo late"},
+	}, 500*time.Millisecond)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if ret, err := env.FromEnvOrDefault(context.Background(), "DB_PASSWORD", "", env.WithEnvLoader(loader)); err != nil || ret != "hunter2" {
+		t.Logf("FromEnvOrDefault returned (%q, %v)", ret, err)
+		t.Fail()
+	}
+	for _, key := range []string{"FAILING", "SLOW", "NOT_ALLOWED"} {
+		if val := loader(key); val != "" {
+			t.Logf("expected %s to be unset, got %q", key, val)
+			t.Fail()
+		}
+	}
+
+	if _, err := env.ExecLoader(map[string][]string{"EMPTY": {}}, time.Second); err == nil {
+		t.Log("expected an error for an empty command")
+		t.Fail()
+	}
+	if _, err := env.ExecLoader(nil, 0); err == nil {
+		t.Log("expected an error for a zero timeout")
+		t.Fail()
+	}
+}
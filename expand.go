@@ -0,0 +1,220 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// defaultExpansionDepth is the number of nested references WithExpansion follows unless changed via WithMaxExpansionDepth.
+	defaultExpansionDepth = 32
+	// defaultExpansionLength is the size in bytes an expanded value may reach unless changed via WithMaxExpansionLength.
+	defaultExpansionLength = 64 << 10
+)
+
+// WithExpansion expands `$VAR` and `${VAR}` references within values before they are parsed, resolving each reference through the parser's loader,
+// e.g. `DATABASE_URL=postgres://$DB_USER:$DB_PASS@$DB_HOST/db`. `${VAR:-default}` uses the default when VAR is unset or empty, and `$$` is a literal `$`.
+//
+// Referenced values are themselves expanded; a reference cycle fails the parse. Each variable is resolved at most once per expansion,
+// and the nesting depth and expanded length are bounded (see WithMaxExpansionDepth and WithMaxExpansionLength) so that self-referencing values cannot blow up.
+func WithExpansion() EnvParseOption {
+	return func(o *envParseOpts) error {
+		o.expand = true
+		return nil
+	}
+}
+
+// WithMaxExpansionDepth limits how many levels of nested references WithExpansion follows, 32 by default. Deeper values fail with an *ExpansionLimitError.
+func WithMaxExpansionDepth(n int) EnvParseOption {
+	return func(o *envParseOpts) error {
+		if n <= 0 {
+			return errors.New("max expansion depth must be positive")
+		}
+
+		o.expandDepth = n
+		return nil
+	}
+}
+
+// WithMaxExpansionLength limits the size in bytes of a value expanded by WithExpansion, 64KiB by default. Longer values fail with an *ExpansionLimitError.
+func WithMaxExpansionLength(n int) EnvParseOption {
+	return func(o *envParseOpts) error {
+		if n <= 0 {
+			return errors.New("max expansion length must be positive")
+		}
+
+		o.expandLength = n
+		return nil
+	}
+}
+
+// ExpandingLoader wraps loader so the values it returns have their references expanded as by WithExpansion, under the default limits.
+// A value that cannot be expanded due to a reference cycle, exceeded limit or malformed reference is returned as written.
+func ExpandingLoader(loader EnvLoader) EnvLoader {
+	return func(key string) string {
+		val := loader(key)
+		expanded, err := newExpander(key, loader, defaultExpansionDepth, defaultExpansionLength).expand(val)
+		if err != nil {
+			return val
+		}
+		return expanded
+	}
+}
+
+// expander expands the references within a single value, resolving each referenced variable at most once.
+type expander struct {
+	key       string
+	lookup    EnvLoader
+	maxDepth  int
+	maxLength int
+	resolved  map[string]expansion
+	// onRef, if set, observes each reference as it resolves.
+	onRef func(name, val string)
+}
+
+// expansion is a resolved reference along with the depth of the references nested within it.
+type expansion struct {
+	val    string
+	height int
+}
+
+func newExpander(key string, lookup EnvLoader, maxDepth, maxLength int) *expander {
+	return &expander{key: key, lookup: lookup, maxDepth: maxDepth, maxLength: maxLength, resolved: make(map[string]expansion)}
+}
+
+// expander returns an expander for the value of envVar, resolving references through the configured loader.
+func (o *envParseOpts) expander(envVar string) *expander {
+	maxDepth, maxLength := o.expandDepth, o.expandLength
+	if maxDepth == 0 {
+		maxDepth = defaultExpansionDepth
+	}
+	if maxLength == 0 {
+		maxLength = defaultExpansionLength
+	}
+	return newExpander(envVar, func(key string) string { return o.load(envVar, key, false) }, maxDepth, maxLength)
+}
+
+// expand expands the references in val, the value of the expander's key.
+func (x *expander) expand(val string) (string, error) {
+	expanded, _, err := x.expandRefs(val, []string{x.key})
+	return expanded, err
+}
+
+// expandRefs expands the references in val, resolving them through lookup. stack holds the keys being expanded, outermost first, to detect cycles.
+// It also returns the depth of the references nested within val.
+func (x *expander) expandRefs(val string, stack []string) (string, int, error) {
+	if !strings.Contains(val, "$") {
+		return val, 0, nil
+	}
+
+	var sb strings.Builder
+	height := 0
+	for i := 0; i < len(val); i++ {
+		if sb.Len() > x.maxLength {
+			return "", 0, &ExpansionLimitError{Key: x.key, Limit: x.maxLength, Length: true}
+		}
+		if val[i] != '$' || i+1 == len(val) {
+			sb.WriteByte(val[i])
+			continue
+		}
+
+		var name, def string
+		hasDefault := false
+		switch next := val[i+1]; {
+		case next == '$':
+			sb.WriteByte('$')
+			i++
+			continue
+		case next == '{':
+			end := closingBrace(val, i+2)
+			if end < 0 {
+				return "", 0, fmt.Errorf("unterminated reference at offset %d", i)
+			}
+			name = val[i+2 : end]
+			name, def, hasDefault = strings.Cut(name, ":-")
+			i = end
+		case isNameByte(next, true):
+			end := i + 1
+			for end < len(val) && isNameByte(val[end], false) {
+				end++
+			}
+			name = val[i+1 : end]
+			i = end - 1
+		default:
+			sb.WriteByte('$')
+			continue
+		}
+		if name == "" {
+			return "", 0, errors.New("empty reference name")
+		}
+
+		ref, err := x.resolve(name, stack)
+		if err != nil {
+			return "", 0, err
+		}
+		height = max(height, ref.height+1)
+		if ref.val == "" && hasDefault {
+			var defHeight int
+			if ref.val, defHeight, err = x.expandRefs(def, stack); err != nil {
+				return "", 0, err
+			}
+			height = max(height, defHeight)
+		}
+		sb.WriteString(ref.val)
+	}
+	if sb.Len() > x.maxLength {
+		return "", 0, &ExpansionLimitError{Key: x.key, Limit: x.maxLength, Length: true}
+	}
+	return sb.String(), height, nil
+}
+
+// resolve returns the expanded value of the variable name, referenced from the value of the last key in stack.
+func (x *expander) resolve(name string, stack []string) (expansion, error) {
+	for _, k := range stack {
+		if k == name {
+			return expansion{}, fmt.Errorf("reference cycle: %s -> %s", strings.Join(stack, " -> "), name)
+		}
+	}
+
+	ref, ok := x.resolved[name]
+	if !ok {
+		if len(stack) > x.maxDepth {
+			return expansion{}, &ExpansionLimitError{Key: x.key, Limit: x.maxDepth}
+		}
+		var err error
+		if ref.val, ref.height, err = x.expandRefs(x.lookup(name), append(stack, name)); err != nil {
+			return expansion{}, err
+		}
+		x.resolved[name] = ref
+	}
+	if len(stack)+ref.height > x.maxDepth {
+		return expansion{}, &ExpansionLimitError{Key: x.key, Limit: x.maxDepth}
+	}
+	if x.onRef != nil {
+		x.onRef(name, ref.val)
+	}
+	return ref, nil
+}
+
+// closingBrace returns the index of the brace closing the reference body starting at start, allowing nested references in defaults, or -1.
+func closingBrace(s string, start int) int {
+	depth := 0
+	for i := start; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			depth++
+		case '}':
+			if depth == 0 {
+				return i
+			}
+			depth--
+		}
+	}
+	return -1
+}
+
+// isNameByte reports whether c may appear in a bare `$VAR` reference name. Names cannot begin with a digit.
+func isNameByte(c byte, first bool) bool {
+	return c == '_' || (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (!first && c >= '0' && c <= '9')
+}
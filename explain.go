@@ -0,0 +1,175 @@
+package env
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Explanation is a step-by-step trace of how a key resolves, intended for debugging which value a service ends up using.
+type Explanation struct {
+	// Key is the env var that was explained.
+	Key string
+	// Steps lists each lookup, transformation and decision in the order they happened.
+	Steps []string
+	// Value is the resolved raw value prior to type conversion. It is masked when the parse is sensitive.
+	Value string
+	// Found is false when no value was resolved and the caller's default would be used.
+	Found bool
+}
+
+// String renders the trace as numbered lines, suitable for printing.
+func (e *Explanation) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "explain %s:\n", e.Key)
+	for i, step := range e.Steps {
+		fmt.Fprintf(&sb, "  %d. %s\n", i+1, step)
+	}
+	return sb.String()
+}
+
+// Explain traces how key would be resolved by this parser without converting it to a destination type.
+// It reports each loader lookup, deprecated and indexed keys consulted, normalization and blank handling, the selected variant, reference expansion,
+// the checks the parsed value will be run through, and whether the default would be used.
+//
+// Any options provided apply to this call only. Values are masked when WithSensitive is set.
+func (p *Parser) Explain(ctx context.Context, key string, opts ...EnvParseOption) (*Explanation, error) {
+	parseOpts := p.opts
+	for _, opt := range opts {
+		if err := opt(&parseOpts); err != nil {
+			return nil, fmt.Errorf("option error: %w", err)
+		}
+	}
+	key = parseOpts.namespace + key
+
+	e := &Explanation{Key: key}
+	show := func(val string) string {
+		if parseOpts.sensitive && val != "" {
+			return "<redacted>"
+		}
+		return fmt.Sprintf("%q", val)
+	}
+
+	base := parseOpts.envLoader
+	parseOpts.envLoader = func(k string) string {
+		val := base(k)
+		if val == "" {
+			e.Steps = append(e.Steps, fmt.Sprintf("looked up %s: unset", k))
+		} else {
+			e.Steps = append(e.Steps, fmt.Sprintf("looked up %s: found %s", k, show(val)))
+		}
+		return val
+	}
+	// routing warnings into the trace also keeps Explain from logging deprecations
+	parseOpts.warningHandler = func(w Warning) {
+		switch w.Kind {
+		case WarnNormalized:
+			e.Steps = append(e.Steps, fmt.Sprintf("normalized %s: stripped byte order mark or trailing carriage return", w.Key))
+		case WarnBlankIsUnset:
+			e.Steps = append(e.Steps, fmt.Sprintf("treated blank %s as unset", w.Key))
+		case WarnDeprecatedKey:
+			e.Steps = append(e.Steps, fmt.Sprintf("using deprecated key %s in place of %s", w.Key, w.EnvVar))
+		}
+	}
+
+	if err := parseOpts.validateKeys(key); err != nil {
+		e.Steps = append(e.Steps, fmt.Sprintf("rejected by key policy: %v", err))
+		return e, nil
+	}
+
+	if parseOpts.machineManaged(key) {
+		e.Steps = append(e.Steps, fmt.Sprintf("%s is machine-managed: set by the platform rather than an operator", key))
+	}
+	val := parseOpts.load(key, key, false)
+	if val == "" && parseOpts.emptyIsSet && parseOpts.setEmpty(key) {
+		e.Found = true
+		e.Steps = append(e.Steps, fmt.Sprintf("%s is set but empty: the empty value is used", key))
+		return e, nil
+	}
+	if val == "" && len(parseOpts.deprecatedKeys) > 0 {
+		var err error
+		if val, err = parseOpts.loadDeprecated(ctx, key, false); err != nil {
+			e.Steps = append(e.Steps, fmt.Sprintf("failed: %v", err))
+			return e, nil
+		}
+	}
+	if val == "" && parseOpts.secretFiles {
+		var err error
+		if val, err = parseOpts.loadFile(key, false); err != nil {
+			e.Steps = append(e.Steps, fmt.Sprintf("failed: %v", err))
+			return e, nil
+		}
+		if val != "" {
+			e.Steps = append(e.Steps, fmt.Sprintf("read value from the file named by %s%s", key, fileSuffix))
+		}
+	}
+	if parseOpts.indexedPrefix != "" {
+		if items := parseOpts.loadIndexed(key, parseOpts.indexedPrefix); len(items) > 0 {
+			e.Found = true
+			e.Value = strings.Join(items, parseOpts.separator)
+			if parseOpts.sensitive {
+				e.Value = "<redacted>"
+			}
+			e.Steps = append(e.Steps, fmt.Sprintf("collected %d indexed values from %s*, used in place of %s for list and map destinations", len(items), parseOpts.indexedPrefix, key))
+			explainChecks(e, &parseOpts)
+			e.Steps = append(e.Steps, "indexed values will be used")
+			return e, nil
+		}
+	}
+
+	if val == "" && parseOpts.required {
+		e.Steps = append(e.Steps, withHint("no value resolved: fails as required", parseOpts.hint))
+		return e, nil
+	}
+	if val == "" {
+		e.Steps = append(e.Steps, "no value resolved: the default will be used")
+		return e, nil
+	}
+	if parseOpts.variantKey != nil {
+		selected, err := selectVariant(val, parseOpts.separator, key+":"+parseOpts.variantKey(ctx))
+		if err != nil {
+			e.Steps = append(e.Steps, fmt.Sprintf("failed: %v", err))
+			return e, nil
+		}
+		if selected != val {
+			e.Steps = append(e.Steps, fmt.Sprintf("selected variant %s of %s", show(selected), show(val)))
+			val = selected
+		}
+	}
+	if parseOpts.expand {
+		exp := parseOpts.expander(key)
+		exp.onRef = func(name, ref string) {
+			e.Steps = append(e.Steps, fmt.Sprintf("expanded reference %s to %s", name, show(ref)))
+		}
+		expanded, err := exp.expand(val)
+		if err != nil {
+			e.Steps = append(e.Steps, fmt.Sprintf("failed: %v", err))
+			return e, nil
+		}
+		if expanded != val {
+			e.Steps = append(e.Steps, fmt.Sprintf("expanded %s to %s", show(val), show(expanded)))
+			val = expanded
+		}
+	}
+	e.Found = true
+	e.Value = val
+	if parseOpts.sensitive {
+		e.Value = "<redacted>"
+	}
+	e.Steps = append(e.Steps, fmt.Sprintf("resolved %s", show(val)))
+	explainChecks(e, &parseOpts)
+	if parseOpts.defaultOnError {
+		e.Steps = append(e.Steps, "falls back to the default if the value fails to parse")
+	}
+	return e, nil
+}
+
+// explainChecks adds a step for each check the parsed value will be run through, in order.
+func explainChecks(e *Explanation, o *envParseOpts) {
+	for _, c := range o.checks {
+		e.Steps = append(e.Steps, "checks the parsed value against "+c.desc)
+	}
+	if o.clamp && len(o.checks) > 0 {
+		e.Steps = append(e.Steps, "clamps out of range values to the nearest bound")
+	}
+}
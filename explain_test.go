@@ -0,0 +1,116 @@
+package env_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ndisidore/go-env"
+)
+
+func TestParserExplain(t *testing.T) {
+	t.Parallel()
+
+	loader := func(key string) string {
+		return map[string]string{"OLD_HOST": "old\r", "TOKEN": "secret"}[key]
+	}
+	p, err := env.NewBuilder().With(env.WithEnvLoader(loader), env.WithDeprecatedKey("OLD_HOST")).Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	e, err := p.Explain(context.Background(), "HOST")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !e.Found || e.Value != "old" {
+		t.Logf("explanation resolved (%q, %t), expected (\"old\", true)", e.Value, e.Found)
+		t.Fail()
+	}
+	out := e.String()
+	for _, want := range []string{"1. looked up HOST: unset", "2. looked up OLD_HOST", "normalized OLD_HOST", "deprecated key OLD_HOST", `resolved "old"`} {
+		if !strings.Contains(out, want) {
+			t.Logf("explanation missing %q:\n%s", want, out)
+			t.Fail()
+		}
+	}
+
+	e, err = p.Explain(context.Background(), "TOKEN", env.WithSensitive(true))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(e.String(), "secret") || e.Value != "<redacted>" {
+		t.Logf("sensitive value leaked into explanation:\n%s", e)
+		t.Fail()
+	}
+
+	bare, err := env.NewBuilder().With(env.WithEnvLoader(loader)).Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	e, err = bare.Explain(context.Background(), "MISSING")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e.Found || !strings.Contains(e.String(), "default will be used") {
+		t.Logf("expected default to be explained:\n%s", e)
+		t.Fail()
+	}
+
+	expanding, err := env.NewBuilder().With(env.WithEnvLoader(func(key string) string {
+		return map[string]string{"URL": "http://$HOST:${PORT:-80}", "HOST": "example.com", "LOOP": "$LOOP"}[key]
+	}), env.WithExpansion()).Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	e, err = expanding.Explain(context.Background(), "URL")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out = e.String()
+	for _, want := range []string{`expanded reference HOST to "example.com"`, `expanded reference PORT to ""`, `expanded "http://$HOST:${PORT:-80}" to "http://example.com:80"`} {
+		if !strings.Contains(out, want) {
+			t.Logf("explanation missing %q:\n%s", want, out)
+			t.Fail()
+		}
+	}
+	if e.Value != "http://example.com:80" {
+		t.Logf("explanation resolved %q, expected the expanded value", e.Value)
+		t.Fail()
+	}
+	if e, err = expanding.Explain(context.Background(), "LOOP"); err != nil || e.Found || !strings.Contains(e.String(), "reference cycle") {
+		t.Logf("expected the expansion failure to be explained, got (%v, %v)", e, err)
+		t.Fail()
+	}
+
+	checked, err := env.NewParser(env.WithEnvLoader(func(key string) string {
+		return map[string]string{"PORT": "8080", "LEVEL": "info@50%,debug@50%", "PEERS_0": "a", "PEERS_1": "b"}[key]
+	}), env.WithMin(1), env.WithMax(65535), env.WithAllowedValues("info", "debug"), env.WithValidation(func(int) error { return nil }), env.WithClamping(),
+		env.WithVariantKey(func(context.Context) string { return "instance-1" }))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	e, err = checked.Explain(context.Background(), "PORT")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out = e.String()
+	for _, want := range []string{"against minimum of 1", "against maximum of 65535", "against allowed values: info, debug", "against custom validation of int values", "clamps out of range"} {
+		if !strings.Contains(out, want) {
+			t.Logf("explanation missing %q:\n%s", want, out)
+			t.Fail()
+		}
+	}
+	if e, err = checked.Explain(context.Background(), "LEVEL"); err != nil || !strings.Contains(e.String(), `selected variant "`) || (e.Value != "info" && e.Value != "debug") {
+		t.Logf("expected the selected variant to be explained, got (%v, %v)", e, err)
+		t.Fail()
+	}
+	e, err = checked.Explain(context.Background(), "PEERS", env.WithIndexedKeys("PEERS_"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !e.Found || !strings.Contains(e.String(), "indexed values will be used") || strings.Contains(e.String(), "default will be used") {
+		t.Logf("expected the indexed values to be explained:\n%s", e)
+		t.Fail()
+	}
+}
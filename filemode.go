@@ -0,0 +1,68 @@
+package env
+
+import (
+	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
+)
+
+// worldWritable is the permission bit allowing anyone to write.
+const worldWritable fs.FileMode = 0o002
+
+// WithWorldWritableWarning reports a WarnWorldWritable warning for parsed fs.FileMode values that allow anyone to write, e.g. for the mode of
+// sensitive files or directories. The value is still used. It does not apply to umasks, where the bit being unset is what makes files world-writable.
+func WithWorldWritableWarning() EnvParseOption {
+	return func(o *envParseOpts) error {
+		o.addCheck("warning for world-writable modes", func(parseOpts *envParseOpts, envVar string, v any) (any, error) {
+			if mode, ok := v.(fs.FileMode); ok && mode&worldWritable != 0 {
+				parseOpts.warn(Warning{Kind: WarnWorldWritable, EnvVar: envVar, Key: envVar})
+			}
+			return v, nil
+		})
+		return nil
+	}
+}
+
+// parseFileMode parses permission bits in octal, e.g. `0644`, `644` or `0o644`, as also used for umasks such as `0022`, or in symbolic form as listed
+// by `ls -l`, e.g. `rw-r--r--`. The setuid, setgid and sticky bits are supported in both forms, e.g. `4755` or `rwsr-xr-x`.
+func parseFileMode(in string) (fs.FileMode, error) {
+	if len(in) == 9 && strings.Trim(in, "rwxsStT-") == "" {
+		return parseSymbolicMode(in)
+	}
+
+	n, err := strconv.ParseUint(strings.TrimPrefix(in, "0o"), 8, 32)
+	if err != nil || n > 0o7777 {
+		return 0, fmt.Errorf("invalid file mode %q (want octal e.g. 0644, or symbolic e.g. rw-r--r--)", in)
+	}
+	mode := fs.FileMode(n) & fs.ModePerm
+	for bit, flag := range map[uint64]fs.FileMode{0o4000: fs.ModeSetuid, 0o2000: fs.ModeSetgid, 0o1000: fs.ModeSticky} {
+		if n&bit != 0 {
+			mode |= flag
+		}
+	}
+	return mode, nil
+}
+
+// parseSymbolicMode parses the nine characters of permissions listed by `ls -l`.
+func parseSymbolicMode(in string) (fs.FileMode, error) {
+	var mode fs.FileMode
+	for i, c := range []byte(in) {
+		bit := fs.FileMode(1) << (8 - i)
+		// the execute position of each class may carry a special bit, shown in uppercase when execute is not set
+		special := [3]fs.FileMode{fs.ModeSetuid, fs.ModeSetgid, fs.ModeSticky}[i/3]
+		specialChar := byte("sst"[i/3])
+		switch {
+		case c == '-':
+		case c == "rwx"[i%3]:
+			mode |= bit
+		case i%3 == 2 && c == specialChar:
+			mode |= bit | special
+		case i%3 == 2 && c == specialChar-'a'+'A':
+			mode |= special
+		default:
+			return 0, fmt.Errorf("invalid symbolic file mode %q: unexpected %q at position %d", in, c, i+1)
+		}
+	}
+	return mode, nil
+}
@@ -0,0 +1,79 @@
+package env_test
+
+import (
+	"context"
+	"io/fs"
+	"strings"
+	"testing"
+
+	"github.com/ndisidore/go-env"
+)
+
+func TestFileMode(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name                string
+		value               string
+		expected            fs.FileMode
+		expectedErrContains string
+	}{
+		{name: "octal", value: "0644", expected: 0o644},
+		{name: "bare octal", value: "755", expected: 0o755},
+		{name: "prefixed octal", value: "0o600", expected: 0o600},
+		{name: "umask", value: "0022", expected: 0o022},
+		{name: "setuid octal", value: "4755", expected: fs.ModeSetuid | 0o755},
+		{name: "sticky octal", value: "1777", expected: fs.ModeSticky | 0o777},
+		{name: "symbolic", value: "rw-r--r--", expected: 0o644},
+		{name: "symbolic setgid", value: "rwxr-sr-x", expected: fs.ModeSetgid | 0o755},
+		{name: "symbolic sticky without execute", value: "rwxrwxrwT", expected: fs.ModeSticky | 0o776},
+		{name: "not octal", value: "0899", expectedErrContains: "want octal e.g. 0644"},
+		{name: "too large", value: "17777", expectedErrContains: "want octal e.g. 0644"},
+		{name: "bad symbol", value: "rw-r--r-s", expectedErrContains: `unexpected 's' at position 9`},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ret, err := env.FromEnvOrDefault(context.Background(), "MODE", fs.FileMode(0), env.WithEnvLoader(func(string) string { return tt.value }))
+			switch {
+			case err != nil && tt.expectedErrContains == "":
+				t.Logf("unexpected error: %v", err)
+				t.Fail()
+			case err != nil:
+				if !strings.Contains(err.Error(), tt.expectedErrContains) {
+					t.Logf("error (%v) does not contain expected (%s)", err, tt.expectedErrContains)
+					t.Fail()
+				}
+			case tt.expectedErrContains != "":
+				t.Logf("expected error containing %q", tt.expectedErrContains)
+				t.Fail()
+			case ret != tt.expected:
+				t.Logf("return value (%v) does not match expected (%v)", ret, tt.expected)
+				t.Fail()
+			}
+		})
+	}
+}
+
+func TestWithWorldWritableWarning(t *testing.T) {
+	t.Parallel()
+
+	loader := func(key string) string {
+		return map[string]string{"SOCKET_MODE": "0666", "KEY_MODE": "rw-------"}[key]
+	}
+	var warnings []env.Warning
+	opts := []env.EnvParseOption{env.WithEnvLoader(loader), env.WithWorldWritableWarning(), env.WithWarningHandler(func(w env.Warning) { warnings = append(warnings, w) })}
+
+	if ret, err := env.FromEnvOrDefault(context.Background(), "SOCKET_MODE", fs.FileMode(0o600), opts...); err != nil || ret != 0o666 {
+		t.Logf("FromEnvOrDefault returned (%v, %v)", ret, err)
+		t.Fail()
+	}
+	if _, err := env.FromEnvOrDefault(context.Background(), "KEY_MODE", fs.FileMode(0o600), opts...); err != nil {
+		t.Logf("unexpected error: %v", err)
+		t.Fail()
+	}
+	if len(warnings) != 1 || warnings[0].Kind != env.WarnWorldWritable || warnings[0].EnvVar != "SOCKET_MODE" {
+		t.Logf("unexpected warnings: %v", warnings)
+		t.Fail()
+	}
+}
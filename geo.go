@@ -0,0 +1,52 @@
+package env
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// LatLng is a destination for a geographic coordinate in decimal degrees, parsed from `37.77,-122.42`, e.g. for geo-fences or a default location.
+// Latitude must be within ±90 and longitude within ±180.
+//
+// As the coordinate itself contains a comma, lists of coordinates are not supported.
+type LatLng struct {
+	Lat float64
+	Lng float64
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (l *LatLng) UnmarshalText(text []byte) error {
+	latStr, lngStr, ok := strings.Cut(string(text), ",")
+	if !ok {
+		return fmt.Errorf("invalid coordinate %q (want e.g. 37.77,-122.42)", text)
+	}
+	lat, err := parseDegrees(latStr, 90, "latitude")
+	if err != nil {
+		return err
+	}
+	lng, err := parseDegrees(lngStr, 180, "longitude")
+	if err != nil {
+		return err
+	}
+	*l = LatLng{Lat: lat, Lng: lng}
+	return nil
+}
+
+// String returns the coordinate in the form it is parsed from.
+func (l LatLng) String() string {
+	return strconv.FormatFloat(l.Lat, 'f', -1, 64) + "," + strconv.FormatFloat(l.Lng, 'f', -1, 64)
+}
+
+// parseDegrees parses a finite number of degrees within ±limit.
+func parseDegrees(in string, limit float64, name string) (float64, error) {
+	deg, err := strconv.ParseFloat(strings.TrimSpace(in), 64)
+	if err != nil || math.IsNaN(deg) || math.IsInf(deg, 0) {
+		return 0, fmt.Errorf("invalid %s %q", name, in)
+	}
+	if math.Abs(deg) > limit {
+		return 0, fmt.Errorf("%s %v is out of range (want -%v to %v)", name, deg, limit, limit)
+	}
+	return deg, nil
+}
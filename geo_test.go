@@ -0,0 +1,47 @@
+package env_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ndisidore/go-env"
+)
+
+func TestLatLng(t *testing.T) {
+	t.Parallel()
+
+	loader := func(key string) string {
+		return map[string]string{
+			"HOME":    "37.77, -122.42",
+			"NORTH":   "90,180",
+			"BAD_LAT": "91,0",
+			"BAD_LNG": "0,-180.5",
+			"NO_LNG":  "37.77",
+			"NOT_NUM": "north,0",
+			"NAN":     "NaN,0",
+		}[key]
+	}
+
+	ret, err := env.FromEnvOrDefaultAny(context.Background(), "HOME", env.LatLng{}, env.WithEnvLoader(loader))
+	if err != nil || ret != (env.LatLng{Lat: 37.77, Lng: -122.42}) || ret.String() != "37.77,-122.42" {
+		t.Logf("FromEnvOrDefault returned (%v, %v)", ret, err)
+		t.Fail()
+	}
+	if ret, err := env.FromEnvOrDefaultAny(context.Background(), "NORTH", env.LatLng{}, env.WithEnvLoader(loader)); err != nil || ret != (env.LatLng{Lat: 90, Lng: 180}) {
+		t.Logf("FromEnvOrDefault returned (%v, %v)", ret, err)
+		t.Fail()
+	}
+	for key, expected := range map[string]string{
+		"BAD_LAT": "latitude 91 is out of range (want -90 to 90)",
+		"BAD_LNG": "longitude -180.5 is out of range (want -180 to 180)",
+		"NO_LNG":  "want e.g. 37.77,-122.42",
+		"NOT_NUM": `invalid latitude "north"`,
+		"NAN":     `invalid latitude "NaN"`,
+	} {
+		if _, err := env.FromEnvOrDefaultAny(context.Background(), key, env.LatLng{}, env.WithEnvLoader(loader)); err == nil || !strings.Contains(err.Error(), expected) {
+			t.Logf("unexpected error for %s: %v", key, err)
+			t.Fail()
+		}
+	}
+}
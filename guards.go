@@ -0,0 +1,96 @@
+package env
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrDecodePanic is returned when a custom type's decoder panics.
+var ErrDecodePanic = errors.New("decoder panicked")
+
+// WithDecodeTimeout bounds how long a custom type's decoder (see Parseable) may run, failing the parse once it elapses or the context is done.
+//
+// A decoder that never returns cannot be stopped; it is abandoned in its own goroutine and its result discarded.
+func WithDecodeTimeout(d time.Duration) EnvParseOption {
+	return func(o *envParseOpts) error {
+		if d <= 0 {
+			return errors.New("decode timeout must be positive")
+		}
+
+		o.decodeTimeout = d
+		return nil
+	}
+}
+
+// WithMaxValueLength rejects values, or list items, longer than n bytes before they are parsed, guarding decoders against oversized input.
+func WithMaxValueLength(n int) EnvParseOption {
+	return func(o *envParseOpts) error {
+		if n <= 0 {
+			return errors.New("max value length must be positive")
+		}
+
+		o.maxValueLength = n
+		return nil
+	}
+}
+
+// checkLength enforces the configured maximum value length against the value and each list item.
+func (o *envParseOpts) checkLength(envStr string, items []string) error {
+	if o.maxValueLength <= 0 {
+		return nil
+	}
+	if len(envStr) > o.maxValueLength {
+		return fmt.Errorf("value length %d exceeds limit of %d bytes", len(envStr), o.maxValueLength)
+	}
+	for i, item := range items {
+		if len(item) > o.maxValueLength {
+			return fmt.Errorf("item (pos: %d) length %d exceeds limit of %d bytes", i, len(item), o.maxValueLength)
+		}
+	}
+	return nil
+}
+
+// guardedDecode runs decodeInto against fresh, a pointer to a copy of the destination, calling commit to store the copy only if decoding succeeds
+// within the configured decode timeout. A decoder that panics fails the parse rather than the program.
+func (o *envParseOpts) guardedDecode(ctx context.Context, fresh any, envStr string, commit func()) error {
+	if o.decodeTimeout <= 0 {
+		if err := recoveredDecode(fresh, envStr); err != nil {
+			return err
+		}
+		commit()
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, o.decodeTimeout)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		done <- recoveredDecode(fresh, envStr)
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			// the timeout fired while the result was being delivered
+			return fmt.Errorf("decoding abandoned: %w", ctx.Err())
+		}
+		commit()
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("decoding abandoned: %w", ctx.Err())
+	}
+}
+
+// recoveredDecode runs decodeInto, reporting a panic as an error wrapping ErrDecodePanic.
+func recoveredDecode(ptr any, envStr string) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v", ErrDecodePanic, r)
+		}
+	}()
+	return decodeInto(ptr, envStr)
+}
@@ -0,0 +1,134 @@
+package env_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ndisidore/go-env"
+)
+
+// slowText blocks in UnmarshalText until released, standing in for a pathological decoder.
+type slowText struct {
+	release chan struct{}
+}
+
+func (s *slowText) UnmarshalText([]byte) error {
+	<-s.release
+	return nil
+}
+
+// lateText decodes only once lateRelease is closed, closing lateWritten once it has written its receiver. Destinations are decoded from their zero
+// value, so the channels cannot be fields.
+type lateText struct {
+	Value string
+}
+
+var lateRelease, lateWritten = make(chan struct{}), make(chan struct{})
+
+func (s *lateText) UnmarshalText(b []byte) error {
+	<-lateRelease
+	s.Value = string(b)
+	close(lateWritten)
+	return nil
+}
+
+// panicText panics in UnmarshalText, standing in for a buggy decoder.
+type panicText struct{}
+
+func (panicText) UnmarshalText([]byte) error {
+	panic("boom")
+}
+
+func TestWithDecodeTimeout(t *testing.T) {
+	t.Parallel()
+
+	loader := func(key string) string {
+		return map[string]string{"SLOW": "x"}[key]
+	}
+	release := make(chan struct{})
+	defer close(release)
+
+	_, err := env.FromEnvOrDefaultAny(context.Background(), "SLOW", slowText{release: release}, env.WithEnvLoader(loader), env.WithDecodeTimeout(10*time.Millisecond))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Logf("unexpected error: %v", err)
+		t.Fail()
+	}
+
+	if _, err := env.FromEnvOrDefault(context.Background(), "SLOW", 0, env.WithDecodeTimeout(0)); err == nil {
+		t.Log("expected an error for a non-positive timeout")
+		t.Fail()
+	}
+}
+
+func TestDecodeAbandonedWrite(t *testing.T) {
+	t.Parallel()
+
+	ret, err := env.FromEnvOrDefaultAny(context.Background(), "LATE", lateText{}, env.WithEnvLoader(func(string) string { return "x" }), env.WithDecodeTimeout(10*time.Millisecond))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Logf("unexpected error: %v", err)
+		t.Fail()
+	}
+
+	// the abandoned decoder writes its own copy, so reading the returned value races with nothing (run with -race)
+	close(lateRelease)
+	<-lateWritten
+	if ret.Value != "" {
+		t.Logf("abandoned decoder wrote %q to the returned value", ret.Value)
+		t.Fail()
+	}
+}
+
+func TestDecodePanic(t *testing.T) {
+	t.Parallel()
+
+	loader := func(string) string { return "x" }
+	for _, opts := range [][]env.EnvParseOption{{env.WithEnvLoader(loader)}, {env.WithEnvLoader(loader), env.WithDecodeTimeout(time.Second)}} {
+		if _, err := env.FromEnvOrDefaultAny(context.Background(), "BUGGY", panicText{}, opts...); !errors.Is(err, env.ErrDecodePanic) || !strings.Contains(err.Error(), "boom") {
+			t.Logf("unexpected error: %v", err)
+			t.Fail()
+		}
+	}
+}
+
+func TestWithMaxValueLength(t *testing.T) {
+	t.Parallel()
+
+	loader := func(key string) string {
+		return map[string]string{"SHORT": "abc", "LONG": "abcdefgh", "LIST": "a,bcdefgh"}[key]
+	}
+	cases := []struct {
+		searchEnv           string
+		expectedErrContains string
+	}{
+		{searchEnv: "SHORT"},
+		{searchEnv: "LONG", expectedErrContains: "value length 8 exceeds limit of 4 bytes"},
+		{searchEnv: "LIST", expectedErrContains: "value length 9 exceeds"},
+	}
+	for _, tt := range cases {
+		_, err := env.FromEnvOrDefault(context.Background(), tt.searchEnv, "", env.WithEnvLoader(loader), env.WithMaxValueLength(4))
+		switch {
+		case err != nil && tt.expectedErrContains != "":
+			if !strings.Contains(err.Error(), tt.expectedErrContains) {
+				t.Logf("unexpected error: %v", err)
+				t.Fail()
+			}
+		case err != nil:
+			t.Logf("unexpected error: %v", err)
+			t.Fail()
+		case tt.expectedErrContains != "":
+			t.Logf("expected error containing %q", tt.expectedErrContains)
+			t.Fail()
+		}
+	}
+
+	_, err := env.FromEnvOrDefault(context.Background(), "ITEMS", []string{}, env.WithEnvLoader(func(key string) string {
+		return map[string]string{"ITEMS_0": "a", "ITEMS_1": "bcdefgh"}[key]
+	}), env.WithIndexedKeys("ITEMS_"), env.WithMaxValueLength(4))
+	if err == nil || !strings.Contains(err.Error(), "item (pos: 1) length 7") {
+		t.Logf("unexpected error: %v", err)
+		t.Fail()
+	}
+}
@@ -0,0 +1,82 @@
+//go:build !tinygo
+
+package env
+
+import (
+	"fmt"
+	"os/user"
+	"strconv"
+	"time"
+)
+
+type (
+	// UID is a destination for a user ID given either numerically, e.g. `1000`, or as a user name, e.g. `nobody`, resolved via os/user at parse time,
+	// for services that drop privileges based on their config.
+	UID uint32
+
+	// GID is a destination for a group ID given either numerically, e.g. `1000`, or as a group name, e.g. `nogroup`, resolved via os/user at parse time.
+	GID uint32
+)
+
+// idLookupTimeout bounds name resolution, which may be backed by a slow directory service such as LDAP. WithDecodeTimeout bounds it further.
+const idLookupTimeout = 5 * time.Second
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (u *UID) UnmarshalText(text []byte) error {
+	id, err := parseID(string(text), "user", func(name string) (string, error) {
+		usr, err := user.Lookup(name)
+		if err != nil {
+			return "", err
+		}
+		return usr.Uid, nil
+	})
+	*u = UID(id)
+	return err
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (g *GID) UnmarshalText(text []byte) error {
+	id, err := parseID(string(text), "group", func(name string) (string, error) {
+		grp, err := user.LookupGroup(name)
+		if err != nil {
+			return "", err
+		}
+		return grp.Gid, nil
+	})
+	*g = GID(id)
+	return err
+}
+
+// parseID parses a numeric ID, or resolves a name to one via lookup, bounded by idLookupTimeout.
+func parseID(in, kind string, lookup func(name string) (string, error)) (uint32, error) {
+	if in == "" {
+		return 0, fmt.Errorf("%s cannot be empty", kind)
+	}
+	if id, err := strconv.ParseUint(in, 10, 32); err == nil {
+		return uint32(id), nil
+	}
+
+	type result struct {
+		id  string
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		id, err := lookup(in)
+		done <- result{id: id, err: err}
+	}()
+	select {
+	case res := <-done:
+		if res.err != nil {
+			return 0, fmt.Errorf("unknown %s %q: %w", kind, in, res.err)
+		}
+		id, err := strconv.ParseUint(res.id, 10, 32)
+		if err != nil {
+			// os/user reports SIDs rather than numeric IDs on Windows
+			return 0, fmt.Errorf("%s %q has non-numeric ID %q", kind, in, res.id)
+		}
+		return uint32(id), nil
+	case <-time.After(idLookupTimeout):
+		return 0, fmt.Errorf("timed out resolving %s %q after %v", kind, in, idLookupTimeout)
+	}
+}
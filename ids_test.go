@@ -0,0 +1,68 @@
+//go:build !tinygo
+
+package env_test
+
+import (
+	"context"
+	"os/user"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/ndisidore/go-env"
+)
+
+func TestUIDAndGID(t *testing.T) {
+	t.Parallel()
+
+	current, err := user.Current()
+	if err != nil {
+		t.Skipf("current user is unavailable: %v", err)
+	}
+	uid, err := strconv.ParseUint(current.Uid, 10, 32)
+	if err != nil {
+		t.Skipf("user IDs are not numeric: %v", err)
+	}
+	gid, _ := strconv.ParseUint(current.Gid, 10, 32)
+	group, err := user.LookupGroupId(current.Gid)
+	if err != nil {
+		t.Skipf("current group is unavailable: %v", err)
+	}
+
+	loader := func(key string) string {
+		return map[string]string{
+			"RUN_AS":       current.Username,
+			"RUN_AS_ID":    "1234",
+			"RUN_GROUP":    group.Name,
+			"BAD_USER":     "no-such-user-for-go-env",
+			"BAD_GROUP":    "no-such-group-for-go-env",
+			"NEGATIVE_UID": "-1",
+		}[key]
+	}
+	ctx := context.Background()
+
+	if ret, err := env.FromEnvOrDefaultAny(ctx, "RUN_AS", env.UID(0), env.WithEnvLoader(loader)); err != nil || ret != env.UID(uid) {
+		t.Logf("FromEnvOrDefault returned (%d, %v)", ret, err)
+		t.Fail()
+	}
+	if ret, err := env.FromEnvOrDefaultAny(ctx, "RUN_AS_ID", env.UID(0), env.WithEnvLoader(loader)); err != nil || ret != 1234 {
+		t.Logf("FromEnvOrDefault returned (%d, %v)", ret, err)
+		t.Fail()
+	}
+	if ret, err := env.FromEnvOrDefaultAny(ctx, "RUN_GROUP", env.GID(1), env.WithEnvLoader(loader)); err != nil || ret != env.GID(gid) {
+		t.Logf("FromEnvOrDefault returned (%d, %v)", ret, err)
+		t.Fail()
+	}
+	if _, err := env.FromEnvOrDefaultAny(ctx, "BAD_USER", env.UID(0), env.WithEnvLoader(loader)); err == nil || !strings.Contains(err.Error(), `unknown user "no-such-user-for-go-env"`) {
+		t.Logf("unexpected error: %v", err)
+		t.Fail()
+	}
+	if _, err := env.FromEnvOrDefaultAny(ctx, "BAD_GROUP", env.GID(0), env.WithEnvLoader(loader)); err == nil || !strings.Contains(err.Error(), `unknown group "no-such-group-for-go-env"`) {
+		t.Logf("unexpected error: %v", err)
+		t.Fail()
+	}
+	if _, err := env.FromEnvOrDefaultAny(ctx, "NEGATIVE_UID", env.UID(0), env.WithEnvLoader(loader)); err == nil {
+		t.Log("expected an error for a negative ID")
+		t.Fail()
+	}
+}
@@ -0,0 +1,91 @@
+//go:build !tinygo
+
+package env
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+)
+
+// InterfaceAddr is a destination that resolves a network interface name, e.g. `eth0`, or a CIDR, e.g. `10.0.0.0/8`, to a local IP at parse time,
+// for services that must bind to a specific NIC. An interface name resolves to its first IPv4 address, or its first address if it has no IPv4 one.
+// A CIDR resolves to the first local address it contains.
+type InterfaceAddr struct {
+	// Interface is the name of the interface the address belongs to.
+	Interface string
+	Addr      netip.Addr
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (a *InterfaceAddr) UnmarshalText(text []byte) error {
+	if prefix, err := netip.ParsePrefix(string(text)); err == nil {
+		return a.fromPrefix(prefix.Masked())
+	}
+
+	iface, err := net.InterfaceByName(string(text))
+	if err != nil {
+		return fmt.Errorf("invalid interface or CIDR %q: %w", text, err)
+	}
+	addrs, err := interfaceAddrs(iface)
+	if err != nil {
+		return err
+	}
+	if len(addrs) == 0 {
+		return fmt.Errorf("interface %s has no addresses", iface.Name)
+	}
+	a.Interface, a.Addr = iface.Name, addrs[0]
+	for _, addr := range addrs {
+		if addr.Is4() {
+			a.Addr = addr
+			break
+		}
+	}
+	return nil
+}
+
+// String returns the resolved address.
+func (a InterfaceAddr) String() string {
+	return a.Addr.String()
+}
+
+// fromPrefix resolves the first local address within prefix.
+func (a *InterfaceAddr) fromPrefix(prefix netip.Prefix) error {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return fmt.Errorf("failed to list interfaces: %w", err)
+	}
+	for _, iface := range ifaces {
+		addrs, err := interfaceAddrs(&iface)
+		if err != nil {
+			return err
+		}
+		for _, addr := range addrs {
+			if prefix.Contains(addr) {
+				a.Interface, a.Addr = iface.Name, addr
+				return nil
+			}
+		}
+	}
+	return errors.New("no local address in " + prefix.String())
+}
+
+// interfaceAddrs lists the IP addresses assigned to iface.
+func interfaceAddrs(iface *net.Interface) ([]netip.Addr, error) {
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list addresses of interface %s: %w", iface.Name, err)
+	}
+	ips := make([]netip.Addr, 0, len(addrs))
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok {
+			continue
+		}
+		if ip, ok := netip.AddrFromSlice(ipNet.IP); ok {
+			ips = append(ips, ip.Unmap())
+		}
+	}
+	return ips, nil
+}
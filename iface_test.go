@@ -0,0 +1,66 @@
+//go:build !tinygo
+
+package env_test
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/ndisidore/go-env"
+)
+
+func TestInterfaceAddr(t *testing.T) {
+	t.Parallel()
+
+	var loopback string
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagLoopback != 0 {
+			loopback = iface.Name
+			break
+		}
+	}
+	if loopback == "" {
+		t.Skip("no loopback interface")
+	}
+
+	loader := func(key string) string {
+		return map[string]string{"BIND_IFACE": loopback, "BIND_CIDR": "127.0.0.0/8", "NO_MATCH": "198.51.100.0/24", "UNKNOWN": "no-such-iface0"}[key]
+	}
+	cases := []struct {
+		searchEnv           string
+		expectedErrContains string
+	}{
+		{searchEnv: "BIND_IFACE"},
+		{searchEnv: "BIND_CIDR"},
+		{searchEnv: "NO_MATCH", expectedErrContains: "no local address in 198.51.100.0/24"},
+		{searchEnv: "UNKNOWN", expectedErrContains: `invalid interface or CIDR "no-such-iface0"`},
+	}
+	for _, tt := range cases {
+		t.Run(tt.searchEnv, func(t *testing.T) {
+			t.Parallel()
+			ret, err := env.FromEnvOrDefaultAny(context.Background(), tt.searchEnv, env.InterfaceAddr{}, env.WithEnvLoader(loader))
+			switch {
+			case err != nil && tt.expectedErrContains != "":
+				if !strings.Contains(err.Error(), tt.expectedErrContains) {
+					t.Logf("unexpected error: %v", err)
+					t.Fail()
+				}
+			case err != nil:
+				t.Logf("unexpected error: %v", err)
+				t.Fail()
+			case tt.expectedErrContains != "":
+				t.Logf("expected error containing %q", tt.expectedErrContains)
+				t.Fail()
+			case ret.Interface != loopback || !ret.Addr.IsLoopback():
+				t.Logf("unexpected resolution: %s on %s", ret, ret.Interface)
+				t.Fail()
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+var (
+	// implementationsMu guards implementations, which maps a typed nil *I (identifying the destination type without reflection) to its factories by name.
+	implementationsMu sync.RWMutex
+	implementations   = map[any]map[string]func() any{}
+)
+
+// RegisterImplementation registers a named implementation of the destination type I, typically an interface.
+// Parsing into an I then selects the implementation whose name matches the value, e.g. `STORAGE_BACKEND=s3`, and returns the result of its factory.
+//
+// It is intended to be called from init functions and panics if the name is empty, the factory is nil, or the name is already registered for I.
+func RegisterImplementation[I any](name string, factory func() I) {
+	if name == "" {
+		panic("env: implementation name cannot be empty string")
+	}
+	if factory == nil {
+		panic("env: implementation factory cannot be nil")
+	}
+
+	implementationsMu.Lock()
+	defer implementationsMu.Unlock()
+	key := any((*I)(nil))
+	impls, ok := implementations[key]
+	if !ok {
+		impls = make(map[string]func() any)
+		implementations[key] = impls
+	}
+	if _, dup := impls[name]; dup {
+		panic(fmt.Sprintf("env: implementation %q registered twice for %T", name, key))
+	}
+	impls[name] = func() any { return factory() }
+}
+
+// hasImplementations reports whether any implementations are registered for the type identified by key.
+func hasImplementations(key any) bool {
+	implementationsMu.RLock()
+	defer implementationsMu.RUnlock()
+	_, ok := implementations[key]
+	return ok
+}
+
+// lookupImplementation resolves name against the implementations registered for the type identified by key.
+// The second return reports whether any implementations are registered for the type.
+func lookupImplementation(key any, name string) (any, bool, error) {
+	implementationsMu.RLock()
+	impls, ok := implementations[key]
+	if !ok {
+		implementationsMu.RUnlock()
+		return nil, false, nil
+	}
+
+	factory, ok := impls[name]
+	if !ok {
+		names := make([]string, 0, len(impls))
+		for n := range impls {
+			names = append(names, n)
+		}
+		implementationsMu.RUnlock()
+		slices.Sort(names)
+		return nil, true, fmt.Errorf("unknown implementation %q (registered: %s)", name, strings.Join(names, ", "))
+	}
+	// the factory runs without the lock held, as it may itself register implementations or parse other destinations
+	implementationsMu.RUnlock()
+
+	impl := factory()
+	if impl == nil {
+		return nil, true, errors.New("implementation factory returned nil")
+	}
+	return impl, true, nil
+}
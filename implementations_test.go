@@ -0,0 +1,95 @@
+package env_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ndisidore/go-env"
+)
+
+type (
+	storage interface{ Name() string }
+
+	s3Storage   struct{}
+	diskStorage struct{}
+
+	codec      interface{ Name() string }
+	gzipCodec  struct{}
+	layered    interface{ Codec() codec }
+	gzipLayers struct{ codec codec }
+)
+
+func (s3Storage) Name() string   { return "s3" }
+func (diskStorage) Name() string { return "disk" }
+func (gzipCodec) Name() string   { return "gzip" }
+
+func (l gzipLayers) Codec() codec { return l.codec }
+
+// codecs numbers the codecs registered by the layered factory, which registers one each time it runs.
+var codecs atomic.Int32
+
+func init() {
+	env.RegisterImplementation[storage]("s3", func() storage { return s3Storage{} })
+	env.RegisterImplementation[storage]("disk", func() storage { return diskStorage{} })
+	// the factory registers and parses implementations of its own, which must not deadlock against the lookup that invoked it
+	env.RegisterImplementation[layered]("gzip", func() layered {
+		env.RegisterImplementation[codec](fmt.Sprintf("gzip-%d", codecs.Add(1)), func() codec { return gzipCodec{} })
+		c, err := env.FromEnvOrDefaultAny[codec](context.Background(), "CODEC", gzipCodec{}, env.WithEnvLoader(func(string) string { return "" }))
+		if err != nil {
+			return nil
+		}
+		return gzipLayers{codec: c}
+	})
+}
+
+func TestRegisterImplementation(t *testing.T) {
+	t.Parallel()
+
+	loader := func(key string) string {
+		return map[string]string{"BACKEND": "s3", "BAD_BACKEND": "gcs"}[key]
+	}
+	ret, err := env.FromEnvOrDefaultAny[storage](context.Background(), "BACKEND", diskStorage{}, env.WithEnvLoader(loader))
+	if err != nil || ret.Name() != "s3" {
+		t.Logf("FromEnvOrDefault returned (%v, %v)", ret, err)
+		t.Fail()
+	}
+
+	ret, err = env.FromEnvOrDefaultAny[storage](context.Background(), "UNSET", diskStorage{}, env.WithEnvLoader(loader))
+	if err != nil || ret.Name() != "disk" {
+		t.Logf("FromEnvOrDefault returned (%v, %v)", ret, err)
+		t.Fail()
+	}
+
+	_, err = env.FromEnvOrDefaultAny[storage](context.Background(), "BAD_BACKEND", nil, env.WithEnvLoader(loader))
+	if err == nil || !strings.Contains(err.Error(), `unknown implementation "gcs" (registered: disk, s3)`) {
+		t.Logf("unexpected error: %v", err)
+		t.Fail()
+	}
+
+	done := make(chan layered, 1)
+	go func() {
+		l, _ := env.FromEnvOrDefaultAny[layered](context.Background(), "LAYERS", nil, env.WithEnvLoader(func(string) string { return "gzip" }))
+		done <- l
+	}()
+	select {
+	case l := <-done:
+		if l == nil || l.Codec().Name() != "gzip" {
+			t.Logf("unexpected implementation: %v", l)
+			t.Fail()
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("factory deadlocked registering and parsing implementations")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Log("expected a duplicate registration to panic")
+			t.Fail()
+		}
+	}()
+	env.RegisterImplementation[storage]("s3", func() storage { return s3Storage{} })
+}
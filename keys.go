@@ -0,0 +1,186 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+type (
+	// KeyLister is an alias for a function that enumerates the keys available to an EnvLoader. It is used for key discovery.
+	KeyLister func() []string
+
+	// keyIndex caches the keys listed by a KeyLister, sorted and deduplicated, so that discovery lists and sorts them once rather than on every call.
+	// A Parser shares one index across its calls; it is dropped whenever the loader or lister is replaced.
+	keyIndex struct {
+		once sync.Once
+		keys []string
+	}
+)
+
+// ErrKeyDiscoveryUnsupported is returned by Parser.Keys when the configured loader has no KeyLister.
+var ErrKeyDiscoveryUnsupported = errors.New("key discovery is not supported by the configured loader")
+
+// PlatformKeys matches keys commonly injected by container orchestrators and cloud runtimes rather than set by operators, for use with WithMachineManagedKeys.
+var PlatformKeys = []string{"KUBERNETES_*", "AWS_*", "ECS_*", "K_SERVICE", "K_REVISION", "K_CONFIGURATION", "HOSTNAME"}
+
+// Keys lists the keys known to the parser's loader that match the provided glob pattern (see path.Match), sorted lexically.
+//
+// The keys are listed once per parser and cached, so keys added to the environment afterwards are only seen by parsers built later.
+// Discovery relies on a KeyLister; the default process environment loader provides one, custom loaders must provide one via WithKeyLister.
+// Keys marked machine-managed via WithMachineManagedKeys are omitted.
+func (p *Parser) Keys(pattern string) ([]string, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("invalid key pattern %q: %w", pattern, err)
+	}
+	if p.opts.keyLister == nil {
+		return nil, ErrKeyDiscoveryUnsupported
+	}
+
+	// only the keys sharing the pattern's literal prefix are matched, avoiding running the matcher against every key in large environments,
+	// e.g. with Kubernetes service links
+	opts := p.opts
+	var matched []string
+	for _, key := range keysWithPrefix(opts.sortedKeys(), literalPrefix(pattern)) {
+		if ok, _ := path.Match(pattern, key); ok && !opts.machineManaged(key) {
+			matched = append(matched, key)
+		}
+	}
+	return matched, nil
+}
+
+// MachineManaged reports whether key was marked as injected by the platform via WithMachineManagedKeys.
+func (p *Parser) MachineManaged(key string) bool {
+	return p.opts.machineManaged(key)
+}
+
+// WithMachineManagedKeys marks keys matching any of the glob patterns (see path.Match) as injected by the platform rather than set by an operator, e.g. PlatformKeys.
+// Such keys can still be parsed, but are left out of key discovery. Patterns are added to any provided previously.
+func WithMachineManagedKeys(patterns ...string) EnvParseOption {
+	return func(o *envParseOpts) error {
+		if len(patterns) == 0 {
+			return errors.New("machine-managed key patterns cannot be empty")
+		}
+		for _, pattern := range patterns {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("invalid key pattern %q: %w", pattern, err)
+			}
+		}
+
+		o.machineManagedKeys = append(slices.Clip(o.machineManagedKeys), patterns...)
+		return nil
+	}
+}
+
+// machineManaged reports whether key matches any of the machine-managed key patterns.
+func (o *envParseOpts) machineManaged(key string) bool {
+	for _, pattern := range o.machineManagedKeys {
+		if !strings.HasPrefix(key, literalPrefix(pattern)) {
+			continue
+		}
+		if ok, _ := path.Match(pattern, key); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// literalPrefix returns the portion of a path.Match pattern before its first special character.
+func literalPrefix(pattern string) string {
+	if i := strings.IndexAny(pattern, `*?[\`); i >= 0 {
+		return pattern[:i]
+	}
+	return pattern
+}
+
+// environKeys lists the keys of the process environment.
+func environKeys() []string {
+	environ := os.Environ()
+	keys := make([]string, 0, len(environ))
+	for _, kv := range environ {
+		key, _, _ := strings.Cut(kv, "=")
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// sortedKeys returns the keys listed by the key lister, sorted and deduplicated, listing them on first use.
+func (o *envParseOpts) sortedKeys() []string {
+	if o.keys == nil {
+		o.keys = new(keyIndex)
+	}
+	o.keys.once.Do(func() {
+		// cloned, as the lister may return a slice it still owns
+		keys := slices.Clone(o.keyLister())
+		slices.Sort(keys)
+		o.keys.keys = slices.Compact(keys)
+	})
+	return o.keys.keys
+}
+
+// keysWithPrefix returns the range of the sorted keys that begin with prefix.
+func keysWithPrefix(keys []string, prefix string) []string {
+	start := sort.SearchStrings(keys, prefix)
+	end := start + sort.Search(len(keys)-start, func(i int) bool {
+		return !strings.HasPrefix(keys[start+i], prefix)
+	})
+	return keys[start:end:end]
+}
+
+// KeyPolicy validates an env var name, returning an error describing why the name is not allowed.
+type KeyPolicy func(key string) error
+
+// POSIXKeys requires names made up of uppercase letters, digits and underscores that do not begin with a digit, per the POSIX portable character set for environment variable names.
+// Names outside this set are silently dropped or mangled by some shells and orchestrators.
+func POSIXKeys(key string) error {
+	if key == "" {
+		return errors.New("name cannot be empty")
+	}
+	for i, r := range key {
+		switch {
+		case r >= 'A' && r <= 'Z', r == '_':
+		case r >= '0' && r <= '9':
+			if i == 0 {
+				return errors.New("name cannot begin with a digit")
+			}
+		default:
+			return fmt.Errorf("character %q is not allowed (want A-Z, 0-9 or _)", r)
+		}
+	}
+	return nil
+}
+
+// WithKeyPolicy validates the env var name, and any deprecated names, against the policy before it is loaded.
+func WithKeyPolicy(policy KeyPolicy) EnvParseOption {
+	return func(o *envParseOpts) error {
+		if policy == nil {
+			return errors.New("key policy cannot be nil")
+		}
+
+		o.keyPolicy = policy
+		return nil
+	}
+}
+
+// validateKeys checks envVar and its deprecated names against the configured key policy, if any.
+func (o *envParseOpts) validateKeys(envVar string) error {
+	if o.keyPolicy == nil {
+		return nil
+	}
+
+	keys := []string{envVar}
+	for _, d := range o.deprecatedKeys {
+		keys = append(keys, d.key)
+	}
+	for _, key := range keys {
+		if err := o.keyPolicy(key); err != nil {
+			return fmt.Errorf("invalid env key %q: %w", key, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+package env_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ndisidore/go-env"
+)
+
+func TestParserKeys(t *testing.T) {
+	t.Parallel()
+
+	vals := map[string]string{"WEBHOOK_URL_ACME": "a", "WEBHOOK_URL_GLOBEX": "b", "OTHER": "c"}
+	loader := func(key string) string { return vals[key] }
+	lister := func() []string {
+		keys := make([]string, 0, len(vals))
+		for k := range vals {
+			keys = append(keys, k)
+		}
+		return keys
+	}
+
+	t.Run("glob", func(t *testing.T) {
+		t.Parallel()
+		p, err := env.NewBuilder().With(env.WithEnvLoader(loader), env.WithKeyLister(lister)).Build()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		keys, err := p.WithOverlay(map[string]string{"WEBHOOK_URL_INITECH": "c"}).Keys("WEBHOOK_URL_*")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if expected := []string{"WEBHOOK_URL_ACME", "WEBHOOK_URL_GLOBEX", "WEBHOOK_URL_INITECH"}; !slices.Equal(keys, expected) {
+			t.Logf("return value (%v) does not match expected (%v)", keys, expected)
+			t.Fail()
+		}
+	})
+
+	t.Run("invalid pattern", func(t *testing.T) {
+		t.Parallel()
+		p, err := env.NewBuilder().With(env.WithEnvLoader(loader), env.WithKeyLister(lister)).Build()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := p.Keys("WEBHOOK_[*"); err == nil {
+			t.Log("expected an error for a malformed pattern")
+			t.Fail()
+		}
+	})
+
+	t.Run("cached", func(t *testing.T) {
+		t.Parallel()
+		var listed atomic.Int32
+		counting := func() []string {
+			listed.Add(1)
+			return lister()
+		}
+		p, err := env.NewParser(env.WithEnvLoader(loader), env.WithKeyLister(counting))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, pattern := range []string{"WEBHOOK_URL_*", "*", "OTHER"} {
+			if _, err := p.Keys(pattern); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		if n := listed.Load(); n != 1 {
+			t.Logf("keys listed %d times, want once per parser", n)
+			t.Fail()
+		}
+
+		// replacing the lister drops the index
+		derived, err := p.Builder().With(env.WithKeyLister(func() []string { return []string{"WEBHOOK_URL_HOOLI"} })).Build()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if keys, err := derived.Keys("WEBHOOK_URL_*"); err != nil || !slices.Equal(keys, []string{"WEBHOOK_URL_HOOLI"}) {
+			t.Logf("Keys returned (%v, %v)", keys, err)
+			t.Fail()
+		}
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		t.Parallel()
+		p, err := env.NewBuilder().With(env.WithEnvLoader(loader)).Build()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := p.Keys("*"); !errors.Is(err, env.ErrKeyDiscoveryUnsupported) {
+			t.Logf("unexpected error: %v", err)
+			t.Fail()
+		}
+	})
+}
+
+func TestWithMachineManagedKeys(t *testing.T) {
+	t.Parallel()
+
+	keys := []string{"KUBERNETES_SERVICE_HOST", "AWS_REGION", "HOSTNAME", "DATABASE_URL", "INTERNAL_TOKEN"}
+	p, err := env.NewParser(
+		env.WithEnvLoader(func(string) string { return "value" }),
+		env.WithKeyLister(func() []string { return keys }),
+		env.WithMachineManagedKeys(env.PlatformKeys...),
+		env.WithMachineManagedKeys("INTERNAL_*"),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	discovered, err := p.Keys("*")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := []string{"DATABASE_URL"}; !slices.Equal(discovered, expected) {
+		t.Logf("return value (%v) does not match expected (%v)", discovered, expected)
+		t.Fail()
+	}
+	if !p.MachineManaged("AWS_REGION") || !p.MachineManaged("INTERNAL_TOKEN") || p.MachineManaged("DATABASE_URL") {
+		t.Log("unexpected machine-managed classification")
+		t.Fail()
+	}
+	if ret, err := env.Get[string](context.Background(), p, "AWS_REGION"); err != nil || ret != "value" {
+		t.Logf("machine-managed keys should still parse, got (%q, %v)", ret, err)
+		t.Fail()
+	}
+
+	if _, err := env.NewParser(env.WithMachineManagedKeys("AWS_[")); err == nil {
+		t.Log("expected an error for a malformed pattern")
+		t.Fail()
+	}
+}
+
+func TestWithKeyPolicy(t *testing.T) {
+	t.Parallel()
+
+	loader := func(key string) string { return "value" }
+	cases := []struct {
+		key                 string
+		options             []env.EnvParseOption
+		expectedErrContains string
+	}{
+		{key: "VALID_KEY_2"},
+		{key: "lower", expectedErrContains: `character 'l' is not allowed`},
+		{key: "HAS SPACE", expectedErrContains: `character ' ' is not allowed`},
+		{key: "2FA_SECRET", expectedErrContains: "cannot begin with a digit"},
+		{key: "VALID", options: []env.EnvParseOption{env.WithDeprecatedKey("old-name")}, expectedErrContains: `invalid env key "old-name"`},
+	}
+	for _, tt := range cases {
+		t.Run(tt.key, func(t *testing.T) {
+			t.Parallel()
+			_, err := env.FromEnvOrDefault(context.Background(), tt.key, "", append(tt.options, env.WithEnvLoader(loader), env.WithKeyPolicy(env.POSIXKeys))...)
+			switch {
+			case err != nil && tt.expectedErrContains != "":
+				if !strings.Contains(err.Error(), tt.expectedErrContains) {
+					t.Logf("unexpected error: %v", err)
+					t.Fail()
+				}
+			case err != nil:
+				t.Logf("unexpected error: %v", err)
+				t.Fail()
+			case tt.expectedErrContains != "":
+				t.Logf("expected error containing %q", tt.expectedErrContains)
+				t.Fail()
+			}
+		})
+	}
+}
+
+// BenchmarkParserKeys mimics a Kubernetes pod with thousands of injected service-link variables.
+func BenchmarkParserKeys(b *testing.B) {
+	keys := make([]string, 0, 5000)
+	for i := 0; i < 1000; i++ {
+		svc := "SVC" + strconv.Itoa(i)
+		keys = append(keys, svc+"_SERVICE_HOST", svc+"_SERVICE_PORT", svc+"_PORT", svc+"_PORT_80_TCP", svc+"_PORT_80_TCP_ADDR")
+	}
+	keys = append(keys, "WEBHOOK_URL_ACME", "WEBHOOK_URL_GLOBEX")
+	p, err := env.NewParser(env.WithEnvLoader(func(string) string { return "" }), env.WithKeyLister(func() []string { return keys }))
+	if err != nil {
+		b.Fatalf("unexpected error: %v", err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if matched, err := p.Keys("WEBHOOK_URL_*"); err != nil || len(matched) != 2 {
+			b.Fatalf("Keys returned (%v, %v)", matched, err)
+		}
+	}
+}
+
+// BenchmarkEnvironKeys lists the keys of a process environment with about 10k variables, as discovered by a freshly built parser.
+func BenchmarkEnvironKeys(b *testing.B) {
+	for i := 0; i < 10000; i++ {
+		b.Setenv("BENCH_SVC"+strconv.Itoa(i)+"_SERVICE_HOST", "10.0.0.1")
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		p, err := env.NewParser()
+		if err != nil {
+			b.Fatalf("unexpected error: %v", err)
+		}
+		if matched, err := p.Keys("BENCH_SVC1_*"); err != nil || len(matched) != 1 {
+			b.Fatalf("Keys returned (%v, %v)", matched, err)
+		}
+	}
+}
@@ -0,0 +1,14 @@
+//go:build !(js && wasm)
+
+package env
+
+import "os"
+
+// platformEnvLoader is the default EnvLoader for this platform. On most platforms, including wasip1, this is simply the process environment.
+var platformEnvLoader EnvLoader = os.Getenv
+
+// platformEnvLookuper is the default EnvLookuper matching platformEnvLoader.
+var platformEnvLookuper EnvLookuper = os.LookupEnv
+
+// platformKeyLister is the default KeyLister matching platformEnvLoader.
+var platformKeyLister KeyLister = environKeys
@@ -0,0 +1,49 @@
+//go:build js && wasm
+
+package env
+
+import (
+	"os"
+	"syscall/js"
+)
+
+// JSEnvGlobal is the name of the JavaScript global object consulted for env vars under js/wasm when the process environment does not define them,
+// e.g. `globalThis.goEnv = {LOG_LEVEL: "debug"}` set by the page before the module starts.
+const JSEnvGlobal = "goEnv"
+
+// platformEnvLoader is the default EnvLoader for js/wasm. Browsers have no process environment, so the JSEnvGlobal object acts as a shim.
+var platformEnvLoader EnvLoader = func(key string) string {
+	val, _ := platformEnvLookuper(key)
+	return val
+}
+
+// platformEnvLookuper is the default EnvLookuper matching platformEnvLoader.
+var platformEnvLookuper EnvLookuper = func(key string) (string, bool) {
+	if val, ok := os.LookupEnv(key); ok {
+		return val, true
+	}
+
+	obj := js.Global().Get(JSEnvGlobal)
+	if obj.Type() != js.TypeObject {
+		return "", false
+	}
+	val := obj.Get(key)
+	if val.Type() != js.TypeString {
+		return "", false
+	}
+	return val.String(), true
+}
+
+// platformKeyLister is the default KeyLister matching platformEnvLoader.
+var platformKeyLister KeyLister = func() []string {
+	keys := environKeys()
+	obj := js.Global().Get(JSEnvGlobal)
+	if obj.Type() != js.TypeObject {
+		return keys
+	}
+	names := js.Global().Get("Object").Call("keys", obj)
+	for i := 0; i < names.Length(); i++ {
+		keys = append(keys, names.Index(i).String())
+	}
+	return keys
+}
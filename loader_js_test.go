@@ -0,0 +1,25 @@
+//go:build js && wasm
+
+package env_test
+
+import (
+	"context"
+	"syscall/js"
+	"testing"
+
+	"github.com/ndisidore/go-env"
+)
+
+func TestJSEnvGlobal(t *testing.T) {
+	js.Global().Set(env.JSEnvGlobal, map[string]any{"JS_ONLY": "42"})
+	defer js.Global().Delete(env.JSEnvGlobal)
+
+	ret, err := env.FromEnvOrDefault(context.Background(), "JS_ONLY", 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ret != 42 {
+		t.Logf("return value (%d) does not match expected (42)", ret)
+		t.Fail()
+	}
+}
@@ -0,0 +1,206 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// LoaderFactory builds a loader from the location and query parameters of a loader URI, e.g. `secret/app` and `ttl=5m` for `vault://secret/app?ttl=5m`.
+type LoaderFactory func(location string, params url.Values) (EnvLoader, error)
+
+var (
+	// loaderFactoriesMu guards loaderFactories, which maps URI schemes to the factory building loaders for them.
+	loaderFactoriesMu sync.RWMutex
+	loaderFactories   = map[string]LoaderFactory{
+		"env": func(string, url.Values) (EnvLoader, error) {
+			return platformEnvLoader, nil
+		},
+		"dotenv": func(location string, _ url.Values) (EnvLoader, error) {
+			return DotEnvLoader(location)
+		},
+		"dir": func(location string, _ url.Values) (EnvLoader, error) {
+			return DirLoader(location)
+		},
+	}
+)
+
+// RegisterLoaderFactory registers the factory used to build loaders for URIs with the given scheme, so the backends a binary reads from can be chosen at runtime via LoaderFromURI.
+// The `env` (process environment), `dotenv` (dotenv file) and `dir` (see DirLoader) schemes are built in.
+//
+// It is intended to be called from init functions and panics if the scheme is empty, the factory is nil, or the scheme is already registered.
+func RegisterLoaderFactory(scheme string, factory LoaderFactory) {
+	if scheme == "" {
+		panic("env: loader scheme cannot be empty string")
+	}
+	if factory == nil {
+		panic("env: loader factory cannot be nil")
+	}
+
+	loaderFactoriesMu.Lock()
+	defer loaderFactoriesMu.Unlock()
+	if _, dup := loaderFactories[scheme]; dup {
+		panic(fmt.Sprintf("env: loader factory %q registered twice", scheme))
+	}
+	loaderFactories[scheme] = factory
+}
+
+// LoaderFromURI builds a loader from a URI of the form `scheme://location?params` using the factory registered for the scheme, e.g. `env://` or `dotenv://.env`.
+// The location is passed to the factory verbatim rather than being split into a host and path.
+func LoaderFromURI(uri string) (EnvLoader, error) {
+	scheme, rest, ok := strings.Cut(uri, "://")
+	if !ok || scheme == "" {
+		return nil, fmt.Errorf("invalid loader URI %q: expected scheme://location", uri)
+	}
+	location, query, _ := strings.Cut(rest, "?")
+	params, err := url.ParseQuery(query)
+	if err != nil {
+		return nil, fmt.Errorf("invalid loader URI %q: %w", uri, err)
+	}
+
+	return newLoader(scheme, location, params)
+}
+
+// newLoader builds a loader using the factory registered for scheme.
+func newLoader(scheme, location string, params url.Values) (EnvLoader, error) {
+	loaderFactoriesMu.RLock()
+	factory, ok := loaderFactories[scheme]
+	loaderFactoriesMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown loader scheme %q (registered: %s)", scheme, strings.Join(registeredSchemes(), ", "))
+	}
+
+	loader, err := factory(location, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build %s loader: %w", scheme, err)
+	}
+	if loader == nil {
+		return nil, fmt.Errorf("failed to build %s loader: factory returned nil", scheme)
+	}
+	return loader, nil
+}
+
+// registeredSchemes lists the registered loader schemes, sorted lexically.
+func registeredSchemes() []string {
+	loaderFactoriesMu.RLock()
+	defer loaderFactoriesMu.RUnlock()
+	schemes := make([]string, 0, len(loaderFactories))
+	for s := range loaderFactories {
+		schemes = append(schemes, s)
+	}
+	slices.Sort(schemes)
+	return schemes
+}
+
+// ChainLoaders returns a loader that consults each loader in order and returns the first non-empty value, e.g. so the process environment overrides
+// a dotenv file which in turn overrides a map of defaults. Nil loaders are skipped.
+func ChainLoaders(loaders ...EnvLoader) EnvLoader {
+	return func(key string) string {
+		for _, loader := range loaders {
+			if loader == nil {
+				continue
+			}
+			if val := loader(key); val != "" {
+				return val
+			}
+		}
+		return ""
+	}
+}
+
+// LoaderFromSpec builds a loader from a small composition language, e.g. `chain(dotenv(.env),env,ssm(/myapp))`, so config sources can be changed without code changes.
+//
+// A spec is either a bare scheme such as `env`, a scheme applied to a location such as `dotenv(.env)` (equivalent to the URI `dotenv://.env`, including
+// any `?params`), or `chain(...)` combining comma separated specs with ChainLoaders.
+func LoaderFromSpec(spec string) (EnvLoader, error) {
+	sp := specParser{spec: spec}
+	loader, err := sp.parse()
+	if err != nil {
+		return nil, fmt.Errorf("invalid loader spec %q: %w", spec, err)
+	}
+	sp.skipSpace()
+	if sp.pos != len(sp.spec) {
+		return nil, fmt.Errorf("invalid loader spec %q: unexpected %q at offset %d", spec, sp.spec[sp.pos:], sp.pos)
+	}
+	return loader, nil
+}
+
+// specParser is a recursive descent parser over a loader spec.
+type specParser struct {
+	spec string
+	pos  int
+}
+
+// parse parses a single spec starting at the current position.
+func (sp *specParser) parse() (EnvLoader, error) {
+	start := sp.pos
+	for sp.pos < len(sp.spec) && !strings.ContainsRune("(),", rune(sp.spec[sp.pos])) {
+		sp.pos++
+	}
+	name := strings.TrimSpace(sp.spec[start:sp.pos])
+	if name == "" {
+		return nil, fmt.Errorf("expected loader name at offset %d", start)
+	}
+	if sp.pos == len(sp.spec) || sp.spec[sp.pos] != '(' {
+		return newLoader(name, "", nil)
+	}
+	sp.pos++
+
+	if name == "chain" {
+		var loaders []EnvLoader
+		for {
+			loader, err := sp.parse()
+			if err != nil {
+				return nil, err
+			}
+			loaders = append(loaders, loader)
+			sp.skipSpace()
+			if sp.pos == len(sp.spec) {
+				return nil, errors.New("unterminated chain(")
+			}
+			switch sp.spec[sp.pos] {
+			case ')':
+				sp.pos++
+				return ChainLoaders(loaders...), nil
+			case ',':
+				sp.pos++
+			default:
+				return nil, fmt.Errorf("expected , or ) at offset %d", sp.pos)
+			}
+		}
+	}
+
+	// the argument runs to the matching close paren so locations may themselves contain parens or commas
+	argStart, depth := sp.pos, 0
+	for ; sp.pos < len(sp.spec); sp.pos++ {
+		switch sp.spec[sp.pos] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		}
+		if depth < 0 {
+			break
+		}
+	}
+	if depth >= 0 {
+		return nil, fmt.Errorf("unterminated %s(", name)
+	}
+	location, query, _ := strings.Cut(strings.TrimSpace(sp.spec[argStart:sp.pos]), "?")
+	sp.pos++
+	params, err := url.ParseQuery(query)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s params: %w", name, err)
+	}
+	return newLoader(name, location, params)
+}
+
+// skipSpace advances past any whitespace.
+func (sp *specParser) skipSpace() {
+	for sp.pos < len(sp.spec) && sp.spec[sp.pos] == ' ' {
+		sp.pos++
+	}
+}
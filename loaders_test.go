@@ -0,0 +1,137 @@
+package env_test
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ndisidore/go-env"
+)
+
+func TestChainLoaders(t *testing.T) {
+	t.Parallel()
+
+	process := func(key string) string {
+		return map[string]string{"HOST": "process"}[key]
+	}
+	dotenv := func(key string) string {
+		return map[string]string{"HOST": "dotenv", "PORT": "9090"}[key]
+	}
+	defaults := func(key string) string {
+		return map[string]string{"HOST": "defaults", "PORT": "8080", "DEBUG": "true"}[key]
+	}
+	loader := env.ChainLoaders(process, nil, dotenv, defaults)
+
+	cases := []struct {
+		key      string
+		expected string
+	}{
+		{key: "HOST", expected: "process"},
+		{key: "PORT", expected: "9090"},
+		{key: "DEBUG", expected: "true"},
+		{key: "UNKNOWN", expected: ""},
+	}
+	for _, tt := range cases {
+		ret, err := env.FromEnvOrDefault(context.Background(), tt.key, "", env.WithEnvLoader(loader))
+		if err != nil || ret != tt.expected {
+			t.Logf("FromEnvOrDefault(%s) returned (%q, %v), expected %q", tt.key, ret, err, tt.expected)
+			t.Fail()
+		}
+	}
+}
+
+func TestLoaderFromURI(t *testing.T) {
+	t.Parallel()
+
+	var gotParams url.Values
+	env.RegisterLoaderFactory("static", func(location string, params url.Values) (env.EnvLoader, error) {
+		gotParams = params
+		return func(key string) string {
+			return map[string]string{"HOST": location}[key]
+		}, nil
+	})
+
+	loader, err := env.LoaderFromURI("static://secret/app?ttl=5m")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ret := loader("HOST"); ret != "secret/app" {
+		t.Logf("loader returned %q, expected the URI location", ret)
+		t.Fail()
+	}
+	if gotParams.Get("ttl") != "5m" {
+		t.Logf("factory received unexpected params: %v", gotParams)
+		t.Fail()
+	}
+
+	path := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(path, []byte("PORT=9090\n"), 0o600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if loader, err = env.LoaderFromURI("dotenv://" + path); err != nil || loader("PORT") != "9090" {
+		t.Logf("dotenv loader failed: %v", err)
+		t.Fail()
+	}
+
+	cases := []struct {
+		uri                 string
+		expectedErrContains string
+	}{
+		{uri: "static", expectedErrContains: "expected scheme://location"},
+		{uri: "vault://secret", expectedErrContains: `unknown loader scheme "vault"`},
+		{uri: "dotenv://" + filepath.Join(t.TempDir(), "missing"), expectedErrContains: "failed to build dotenv loader"},
+	}
+	for _, tt := range cases {
+		if _, err := env.LoaderFromURI(tt.uri); err == nil || !strings.Contains(err.Error(), tt.expectedErrContains) {
+			t.Logf("unexpected error for %s: %v", tt.uri, err)
+			t.Fail()
+		}
+	}
+}
+
+func TestLoaderFromSpec(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	local, shared := filepath.Join(dir, "local.env"), filepath.Join(dir, "shared.env")
+	if err := os.WriteFile(local, []byte("HOST=local\n"), 0o600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := os.WriteFile(shared, []byte("HOST=shared\nPORT=9090\n"), 0o600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	loader, err := env.LoaderFromSpec("chain(dotenv(" + local + "), chain(env, dotenv(" + shared + ")))")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ret := loader("HOST"); ret != "local" {
+		t.Logf("HOST resolved to %q, expected the first loader to win", ret)
+		t.Fail()
+	}
+	if ret := loader("PORT"); ret != "9090" {
+		t.Logf("PORT resolved to %q, expected the nested chain to be consulted", ret)
+		t.Fail()
+	}
+
+	cases := []struct {
+		spec                string
+		expectedErrContains string
+	}{
+		{spec: "", expectedErrContains: "expected loader name"},
+		{spec: "chain(env", expectedErrContains: "unterminated chain("},
+		{spec: "dotenv(.env", expectedErrContains: "unterminated dotenv("},
+		{spec: "chain(env dotenv(.env))", expectedErrContains: "unknown loader scheme"},
+		{spec: "env)", expectedErrContains: `unexpected ")"`},
+		{spec: "nope(/x)", expectedErrContains: `unknown loader scheme "nope"`},
+	}
+	for _, tt := range cases {
+		if _, err := env.LoaderFromSpec(tt.spec); err == nil || !strings.Contains(err.Error(), tt.expectedErrContains) {
+			t.Logf("unexpected error for %q: %v", tt.spec, err)
+			t.Fail()
+		}
+	}
+}
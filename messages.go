@@ -0,0 +1,60 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+)
+
+type (
+	// MessageKey identifies a user-facing error message that can be replaced via WithErrorMessages.
+	MessageKey string
+
+	// ErrorMessages maps message keys to fmt format strings. Formats should use explicit argument indexes (e.g. `%[2]s`) so translations may reorder arguments.
+	ErrorMessages map[MessageKey]string
+)
+
+const (
+	// MsgParseFailed is used when a value cannot be parsed. Arguments: env var, destination type, cause.
+	MsgParseFailed MessageKey = "parse_failed"
+	// MsgCastFailed is used when a parsed value cannot be converted to the destination. Arguments: env var, destination type.
+	MsgCastFailed MessageKey = "cast_failed"
+	// MsgKeyRemoved is used when a deprecated key is read after its sunset date. Arguments: deprecated key, sunset date, replacement env var.
+	MsgKeyRemoved MessageKey = "key_removed"
+	// MsgRequired is used when a required env var is unset. Arguments: env var.
+	MsgRequired MessageKey = "required"
+)
+
+var defaultErrorMessages = ErrorMessages{
+	MsgParseFailed: "failed to parse env %[1]s to %[2]s: %[3]v",
+	MsgCastFailed:  "failed to cast env %[1]s to %[2]s",
+	MsgKeyRemoved:  "env %[1]s was removed on %[2]s, use %[3]s instead",
+	MsgRequired:    "env %[1]s is required but not set",
+}
+
+// WithErrorMessages overrides the format of user-facing error messages, e.g. to translate them. Keys absent from the catalog keep their default message.
+func WithErrorMessages(catalog ErrorMessages) EnvParseOption {
+	return func(o *envParseOpts) error {
+		if catalog == nil {
+			return errors.New("error message catalog cannot be nil")
+		}
+
+		merged := make(ErrorMessages, len(o.messages)+len(catalog))
+		for k, v := range o.messages {
+			merged[k] = v
+		}
+		for k, v := range catalog {
+			merged[k] = v
+		}
+		o.messages = merged
+		return nil
+	}
+}
+
+// message formats the message for key, preferring the configured catalog.
+func (o *envParseOpts) message(key MessageKey, args ...any) string {
+	format, ok := o.messages[key]
+	if !ok {
+		format = defaultErrorMessages[key]
+	}
+	return fmt.Sprintf(format, args...)
+}
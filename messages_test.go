@@ -0,0 +1,46 @@
+package env_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ndisidore/go-env"
+)
+
+func TestWithErrorMessages(t *testing.T) {
+	t.Parallel()
+
+	loader := func(key string) string {
+		return map[string]string{"NOT_INT": "abcd", "OLD": "1"}[key]
+	}
+	catalog := env.ErrorMessages{
+		env.MsgParseFailed: "impossible d'analyser %[1]s en %[2]s",
+		env.MsgKeyRemoved:  "%[1]s supprimée le %[2]s",
+	}
+
+	_, err := env.FromEnvOrDefault(context.Background(), "NOT_INT", 0, env.WithEnvLoader(loader), env.WithErrorMessages(catalog))
+	if err == nil || err.Error() != "impossible d'analyser NOT_INT en int" {
+		t.Logf("unexpected error: %v", err)
+		t.Fail()
+	}
+	if !errors.Is(err, strconv.ErrSyntax) {
+		t.Logf("translated error (%v) does not wrap the cause", err)
+		t.Fail()
+	}
+
+	_, err = env.FromEnvOrDefault(context.Background(), "NEW", 0, env.WithEnvLoader(loader), env.WithErrorMessages(catalog),
+		env.WithDeprecatedKey("OLD", env.RemovedAfter(time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC))))
+	if err == nil || err.Error() != "OLD supprimée le 2020-01-01" {
+		t.Logf("unexpected error: %v", err)
+		t.Fail()
+	}
+
+	_, err = env.FromEnvOrDefault(context.Background(), "NOT_INT", 0, env.WithEnvLoader(loader))
+	if err == nil || err.Error() != `failed to parse env NOT_INT to int: strconv.Atoi: parsing "abcd": invalid syntax` {
+		t.Logf("unexpected default error: %v", err)
+		t.Fail()
+	}
+}
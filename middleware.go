@@ -0,0 +1,72 @@
+package env
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// LoaderMiddleware wraps a loader to add cross-cutting behavior, such as caching or logging, without changing where values come from.
+// ExpandingLoader is one, as are the functions of this file ending in Middleware.
+type LoaderMiddleware func(EnvLoader) EnvLoader
+
+// ComposeMiddleware combines middlewares into one, applying them so that the first is outermost, i.e. sees each lookup first. Nil middlewares are skipped.
+func ComposeMiddleware(middlewares ...LoaderMiddleware) LoaderMiddleware {
+	return func(loader EnvLoader) EnvLoader {
+		for i := len(middlewares) - 1; i >= 0; i-- {
+			if middlewares[i] != nil {
+				loader = middlewares[i](loader)
+			}
+		}
+		return loader
+	}
+}
+
+// CachingMiddleware memoizes lookups for ttl, as by CachedLoader.
+func CachingMiddleware(ttl time.Duration, opts ...LoaderOption) LoaderMiddleware {
+	return func(loader EnvLoader) EnvLoader {
+		return CachedLoader(loader, ttl, opts...)
+	}
+}
+
+// PrefixMiddleware looks up each key with prefix prepended, e.g. `PORT` as `MYAPP_PORT`.
+func PrefixMiddleware(prefix string) LoaderMiddleware {
+	return func(loader EnvLoader) EnvLoader {
+		return func(key string) string {
+			return loader(prefix + key)
+		}
+	}
+}
+
+// LoggingMiddleware logs each lookup at debug level via logger, or slog's default logger if nil. Only the key and whether it is set are logged,
+// never the value.
+func LoggingMiddleware(logger *slog.Logger) LoaderMiddleware {
+	return func(loader EnvLoader) EnvLoader {
+		return func(key string) string {
+			l := logger
+			if l == nil {
+				l = slog.Default()
+			}
+			val := loader(key)
+			l.LogAttrs(context.Background(), slog.LevelDebug, "env var loaded", slog.String("env_var", key), slog.Bool("set", val != ""))
+			return val
+		}
+	}
+}
+
+// MetricsMiddleware reports each lookup to observe, along with whether the key is set and how long the lookup took, e.g. to feed a latency histogram.
+// A nil observe leaves the loader unchanged. Lookups are timed by the system clock unless another is provided via WithLoaderClock.
+func MetricsMiddleware(observe func(key string, set bool, took time.Duration), opts ...LoaderOption) LoaderMiddleware {
+	clock := newLoaderOpts(opts).clock
+	return func(loader EnvLoader) EnvLoader {
+		if observe == nil {
+			return loader
+		}
+		return func(key string) string {
+			start := clock.Now()
+			val := loader(key)
+			observe(key, val != "", clock.Now().Sub(start))
+			return val
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package env_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ndisidore/go-env"
+)
+
+func TestComposeMiddleware(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	inner := func(key string) string {
+		calls++
+		return map[string]string{"MYAPP_PORT": "8080", "MYAPP_TOKEN": "s3cret"}[key]
+	}
+	var (
+		logs     bytes.Buffer
+		observed []string
+	)
+	loader := env.ComposeMiddleware(
+		env.MetricsMiddleware(func(key string, set bool, _ time.Duration) {
+			if set {
+				observed = append(observed, key)
+			}
+		}),
+		env.LoggingMiddleware(slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))),
+		nil,
+		env.CachingMiddleware(time.Minute),
+		env.PrefixMiddleware("MYAPP_"),
+	)(inner)
+
+	for range 2 {
+		if port, err := env.FromEnvOrDefault(context.Background(), "PORT", 0, env.WithEnvLoader(loader)); err != nil || port != 8080 {
+			t.Logf("FromEnvOrDefault returned (%d, %v)", port, err)
+			t.Fail()
+		}
+	}
+	if loader("TOKEN") != "s3cret" || loader("MISSING") != "" {
+		t.Log("unexpected values through the middleware")
+		t.Fail()
+	}
+
+	if calls != 3 {
+		t.Logf("inner loader called %d times, want 3 as lookups are cached", calls)
+		t.Fail()
+	}
+	if strings.Join(observed, ",") != "PORT,PORT,TOKEN" {
+		t.Logf("unexpected observed keys: %v", observed)
+		t.Fail()
+	}
+	if !strings.Contains(logs.String(), "env_var=TOKEN set=true") || !strings.Contains(logs.String(), "env_var=MISSING set=false") || strings.Contains(logs.String(), "s3cret") {
+		t.Logf("unexpected logs: %s", logs.String())
+		t.Fail()
+	}
+
+	if expanded := env.ComposeMiddleware(env.ExpandingLoader)(func(key string) string { return map[string]string{"A": "$B", "B": "b"}[key] }); expanded("A") != "b" {
+		t.Log("expected ExpandingLoader to compose as a middleware")
+		t.Fail()
+	}
+}
+
+func TestMetricsMiddlewareClock(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock(time.Date(2026, time.October, 15, 0, 0, 0, 0, time.UTC))
+	slow := func(string) string {
+		clock.Advance(250 * time.Millisecond)
+		return "8080"
+	}
+	var took time.Duration
+	loader := env.MetricsMiddleware(func(_ string, _ bool, d time.Duration) { took = d }, env.WithLoaderClock(clock))(slow)
+	if loader("PORT") != "8080" || took != 250*time.Millisecond {
+		t.Logf("lookup observed as taking %s, want 250ms as measured by the clock", took)
+		t.Fail()
+	}
+}
@@ -0,0 +1,47 @@
+package env
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+)
+
+// WithExitCode sets the exit code used when a Must function fails, so orchestrators can tell configuration failures apart from crashes. The default is 1.
+func WithExitCode(code int) EnvParseOption {
+	return func(o *envParseOpts) error {
+		if code < 1 || code > 255 {
+			return errors.New("exit code must be between 1 and 255")
+		}
+
+		o.exitCode = code
+		return nil
+	}
+}
+
+// WithJSONFailureRecord informs Must functions to report a failure as a single-line JSON record on stderr instead of logging it.
+// The record carries the same fields as an element of Errors.JSON.
+func WithJSONFailureRecord() EnvParseOption {
+	return func(o *envParseOpts) error {
+		o.jsonFailure = true
+		return nil
+	}
+}
+
+// fatal reports a failure to parse envVar and exits the process.
+func (o *envParseOpts) fatal(ctx context.Context, envVar string, err error) {
+	if o.jsonFailure {
+		rec := newErrorRecord(err)
+		if rec.EnvVar == "" {
+			rec.EnvVar = envVar
+		}
+		if line, marshalErr := json.Marshal(rec); marshalErr == nil {
+			fmt.Fprintln(os.Stderr, string(line))
+		}
+	} else {
+		slog.Default().ErrorContext(ctx, "failed to parse env var", slog.String("env_var", envVar), slog.String("error", err.Error()))
+	}
+	os.Exit(o.exitCode)
+}
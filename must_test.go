@@ -0,0 +1,52 @@
+package env_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/ndisidore/go-env"
+)
+
+// TestMustFailureContract re-runs the test binary so the Must call can exit without ending the test run.
+func TestMustFailureContract(t *testing.T) {
+	t.Parallel()
+
+	if os.Getenv("GO_ENV_MUST_CHILD") == "1" {
+		loader := func(key string) string {
+			return map[string]string{"PORT": "abc"}[key]
+		}
+		env.MustFromEnvOrDefault(context.Background(), "PORT", 0, env.WithEnvLoader(loader), env.WithHint("use a port number"),
+			env.WithExitCode(78), env.WithJSONFailureRecord())
+		return
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestMustFailureContract$")
+	cmd.Env = append(os.Environ(), "GO_ENV_MUST_CHILD=1")
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 78 {
+		t.Fatalf("expected exit code 78, got %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(stderr.String()), "\n")
+	var rec map[string]string
+	if err := json.Unmarshal([]byte(lines[len(lines)-1]), &rec); err != nil {
+		t.Fatalf("stderr does not end with a JSON record: %q", stderr.String())
+	}
+	if rec["env_var"] != "PORT" || rec["type"] != "int" || rec["hint"] != "use a port number" || rec["error"] == "" {
+		t.Logf("unexpected failure record: %v", rec)
+		t.Fail()
+	}
+
+	if _, err := env.FromEnvOrDefault(context.Background(), "PORT", 0, env.WithExitCode(0)); err == nil {
+		t.Log("expected an error for an out of range exit code")
+		t.Fail()
+	}
+}
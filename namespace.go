@@ -0,0 +1,21 @@
+package env
+
+// Namespace returns a parser with the package default options whose lookups resolve keys within prefix, e.g. `PORT` as `MYAPP_PORT`.
+// See Parser.Namespace.
+func Namespace(prefix string) *Parser {
+	p := &Parser{opts: loadDefaultParseOptions()}
+	p.opts.keys = new(keyIndex)
+	return p.Namespace(prefix)
+}
+
+// Namespace returns a derived parser whose lookups resolve keys within prefix, e.g. `PORT` as `MYAPP_PORT`, so call sites need not concatenate
+// prefixes by hand. Namespaces nest, so a namespace `DB_` within `MYAPP_` resolves `HOST` as `MYAPP_DB_HOST`. Errors, warnings and explanations name
+// the full key.
+//
+// The prefix applies to the keys passed to the parser, including struct tags, but not to keys passed to options such as WithDeprecatedKey, nor to the
+// patterns passed to Keys.
+func (p *Parser) Namespace(prefix string) *Parser {
+	derived := &Parser{opts: p.opts}
+	derived.opts.namespace += prefix
+	return derived
+}
@@ -0,0 +1,65 @@
+package env_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ndisidore/go-env"
+)
+
+func TestNamespace(t *testing.T) {
+	t.Parallel()
+
+	loader := func(key string) string {
+		return map[string]string{"MYAPP_PORT": "8080", "MYAPP_DB_HOST": "db.internal", "MYAPP_DB_PORT": "x", "PORT": "9090"}[key]
+	}
+	p, err := env.NewParser(env.WithEnvLoader(loader))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	app := p.Namespace("MYAPP_")
+	db := app.Namespace("DB_")
+	ctx := context.Background()
+
+	if port, err := env.Get[int](ctx, app, "PORT"); err != nil || port != 8080 {
+		t.Logf("Get returned (%d, %v)", port, err)
+		t.Fail()
+	}
+	if host, err := env.Get[string](ctx, db, "HOST"); err != nil || host != "db.internal" {
+		t.Logf("Get returned (%q, %v)", host, err)
+		t.Fail()
+	}
+	if port, err := env.Get[int](ctx, p, "PORT"); err != nil || port != 9090 {
+		t.Logf("expected the original parser to be unaffected, got (%d, %v)", port, err)
+		t.Fail()
+	}
+
+	var pe *env.ParseError
+	if _, err := env.Get[int](ctx, db, "PORT"); !errors.As(err, &pe) || pe.EnvVar != "MYAPP_DB_PORT" {
+		t.Logf("expected a parse error naming the full key, got %v", err)
+		t.Fail()
+	}
+	var me *env.MissingError
+	if _, err := env.GetOrDefault(ctx, db, "USER", "", env.WithRequired()); !errors.As(err, &me) || me.EnvVar != "MYAPP_DB_USER" {
+		t.Logf("expected a missing error naming the full key, got %v", err)
+		t.Fail()
+	}
+	if user, err := env.GetOrNil[string](ctx, db, "USER"); err != nil || user != nil {
+		t.Logf("GetOrNil returned (%v, %v)", user, err)
+		t.Fail()
+	}
+
+	if e, err := db.Explain(ctx, "HOST"); err != nil || e.Key != "MYAPP_DB_HOST" || !e.Found {
+		t.Logf("Explain returned (%+v, %v)", e, err)
+		t.Fail()
+	}
+}
+
+func TestNamespaceDefaults(t *testing.T) {
+	t.Setenv("GOENV_NS_TEST_PORT", "7070")
+	if port, err := env.Get[int](context.Background(), env.Namespace("GOENV_NS_TEST_"), "PORT"); err != nil || port != 7070 {
+		t.Logf("Get returned (%d, %v)", port, err)
+		t.Fail()
+	}
+}
@@ -1,36 +1,117 @@
 package env
 
 import (
+	"context"
+	"encoding/base64"
 	"errors"
-	"os"
+	"fmt"
+	"sync"
 	"time"
 )
 
 type (
 	envParseOpts struct {
-		envLoader      EnvLoader
-		separator      string
-		defaultOnError bool
-		timeLayout     string
-		sensitive      bool
+		envLoader          EnvLoader
+		envLookuper        EnvLookuper
+		keyLister          KeyLister
+		listerSet          bool
+		keys               *keyIndex
+		separator          string
+		defaultOnError     bool
+		timeLayout         string
+		sensitive          bool
+		blankIsUnset       bool
+		emptyIsSet         bool
+		normalize          bool
+		validUTF8          bool
+		rawBytes           bool
+		indexedPrefix      string
+		deprecatedKeys     []deprecatedKey
+		messages           ErrorMessages
+		clock              Clock
+		keyPolicy          KeyPolicy
+		requireUnit        bool
+		warningHandler     WarningHandler
+		required           bool
+		decodeTimeout      time.Duration
+		maxValueLength     int
+		provenance         map[string]Provenance
+		keyPrefix          string
+		hint               string
+		exitCode           int
+		jsonFailure        bool
+		variantKey         func(ctx context.Context) string
+		base64             *base64.Encoding
+		windows            map[string]Window
+		machineManagedKeys []string
+		checks             []valueCheck
+		clamp              bool
+		stats              *statsRecorder
+		maxErrors          int
+		expand             bool
+		expandDepth        int
+		expandLength       int
+		secretFiles        bool
+		defaultFunc        func() (any, error)
+		saturate           bool
+		roundDurations     bool
+		namespace          string
+		strictBool         bool
+		unknownKeys        bool
 	}
 
 	// EnvLoader is an alias for a function that loads values from the env. It mirrors the signature of os.Getenv.
 	EnvLoader func(key string) string
 
+	// EnvLookuper is an alias for a function that loads values from the env, also reporting whether the key is present. It mirrors the signature of os.LookupEnv.
+	EnvLookuper func(key string) (string, bool)
+
 	// EnvParseOption is a means to customize parse options via variadic parameters.
 	EnvParseOption func(o *envParseOpts) error
 )
 
 var (
-	defaultParseOptions = envParseOpts{
-		envLoader:      os.Getenv,
+	builtinParseOptions = envParseOpts{
+		envLoader:      platformEnvLoader,
+		envLookuper:    platformEnvLookuper,
+		keyLister:      platformKeyLister,
 		separator:      ",",
 		defaultOnError: false,
 		timeLayout:     time.RFC3339,
+		normalize:      true,
+		clock:          realClock{},
+		exitCode:       1,
 	}
+
+	// defaultParseOptionsMu guards defaultParseOptions, which is read on every FromEnvOrDefault call and may be replaced at any time via SetDefaultOptions.
+	defaultParseOptionsMu sync.RWMutex
+	defaultParseOptions   = builtinParseOptions
 )
 
+// SetDefaultOptions replaces the package-wide defaults used by FromEnvOrDefault and NewBuilder. The options are applied on top of the built-in defaults, not the current ones.
+//
+// It is safe to call concurrently with parsing, although it is intended to be called once during program initialization.
+func SetDefaultOptions(opts ...EnvParseOption) error {
+	o := builtinParseOptions
+	for _, opt := range opts {
+		if err := opt(&o); err != nil {
+			return fmt.Errorf("option error: %w", err)
+		}
+	}
+
+	defaultParseOptionsMu.Lock()
+	defer defaultParseOptionsMu.Unlock()
+	defaultParseOptions = o
+	return nil
+}
+
+// loadDefaultParseOptions returns a copy of the current package-wide defaults.
+func loadDefaultParseOptions() envParseOpts {
+	defaultParseOptionsMu.RLock()
+	defer defaultParseOptionsMu.RUnlock()
+	return defaultParseOptions
+}
+
 // WithEnvLoader allows overriding how env vars are loaded.
 //
 // Primarily used for testing, but feel free to get creative.
@@ -41,6 +122,47 @@ func WithEnvLoader(loader EnvLoader) EnvParseOption {
 		}
 
 		o.envLoader = loader
+		o.envLookuper = nil
+		if !o.listerSet {
+			o.keyLister = nil
+		}
+		o.keys = nil
+		return nil
+	}
+}
+
+// WithEnvLookuper is WithEnvLoader for a loader that also reports whether a key is present, allowing an empty value to be told apart from an unset one (see WithEmptyIsSet).
+func WithEnvLookuper(lookuper EnvLookuper) EnvParseOption {
+	return func(o *envParseOpts) error {
+		if lookuper == nil {
+			return errors.New("env lookuper function cannot be nil")
+		}
+
+		o.envLoader = func(key string) string {
+			val, _ := lookuper(key)
+			return val
+		}
+		o.envLookuper = lookuper
+		if !o.listerSet {
+			o.keyLister = nil
+		}
+		o.keys = nil
+		return nil
+	}
+}
+
+// WithKeyLister allows providing key discovery for a custom loader, used by Parser.Keys.
+//
+// Overriding the env loader disables key discovery unless a lister is also provided.
+func WithKeyLister(lister KeyLister) EnvParseOption {
+	return func(o *envParseOpts) error {
+		if lister == nil {
+			return errors.New("key lister function cannot be nil")
+		}
+
+		o.keyLister = lister
+		o.listerSet = true
+		o.keys = nil
 		return nil
 	}
 }
@@ -65,6 +187,21 @@ func WithFallbackToDefaultOnError(fallback bool) EnvParseOption {
 	}
 }
 
+// WithDefaultFunc computes the default value when it is needed, i.e. when the env var is unset or WithFallbackToDefaultOnError applies, in place of the default passed by the caller.
+// This suits defaults that are costly or environment dependent, e.g. derived from container limits. An error from fn fails the parse.
+//
+// The caller's default is still used for destinations of any type other than T.
+func WithDefaultFunc[T any](fn func() (T, error)) EnvParseOption {
+	return func(o *envParseOpts) error {
+		if fn == nil {
+			return errors.New("default function cannot be nil")
+		}
+
+		o.defaultFunc = func() (any, error) { return fn() }
+		return nil
+	}
+}
+
 // WithTimeLayout allows overriding the time layout used to parse time.Time values. Default is RFC3339.
 func WithTimeLayout(layout string) EnvParseOption {
 	return func(o *envParseOpts) error {
@@ -84,3 +221,139 @@ func WithSensitive(sensitive bool) EnvParseOption {
 		return nil
 	}
 }
+
+// WithBlankIsUnset informs the parser that values consisting solely of whitespace should be treated as unset, falling back to the default value.
+func WithBlankIsUnset() EnvParseOption {
+	return func(o *envParseOpts) error {
+		o.blankIsUnset = true
+		return nil
+	}
+}
+
+// WithEmptyIsSet controls whether a variable that is set to the empty string is used as an explicit empty value, e.g. an empty string or list,
+// rather than being treated as unset and falling back to the default. Types without an empty form, such as int, fail to parse.
+//
+// Presence can only be determined by the default loader and loaders provided via WithEnvLookuper; with any other loader, empty values remain unset.
+func WithEmptyIsSet(set bool) EnvParseOption {
+	return func(o *envParseOpts) error {
+		o.emptyIsSet = set
+		return nil
+	}
+}
+
+// WithNormalization controls whether a leading UTF-8 byte order mark and trailing carriage returns are stripped from values before parsing.
+// These are common artifacts of files edited on Windows and are enabled by default.
+func WithNormalization(normalize bool) EnvParseOption {
+	return func(o *envParseOpts) error {
+		o.normalize = normalize
+		return nil
+	}
+}
+
+// WithValidUTF8 informs the parser that string destinations (including []string) must contain valid UTF-8, returning an error otherwise.
+func WithValidUTF8() EnvParseOption {
+	return func(o *envParseOpts) error {
+		o.validUTF8 = true
+		return nil
+	}
+}
+
+// WithRawBytes informs the parser that []byte destinations should receive the exact bytes of the value, bypassing normalization and blank handling.
+func WithRawBytes() EnvParseOption {
+	return func(o *envParseOpts) error {
+		o.rawBytes = true
+		return nil
+	}
+}
+
+// WithBase64 informs the parser that []byte destinations hold base64, decoded with the given encoding, e.g. base64.StdEncoding or base64.RawURLEncoding.
+func WithBase64(enc *base64.Encoding) EnvParseOption {
+	return func(o *envParseOpts) error {
+		if enc == nil {
+			return errors.New("base64 encoding cannot be nil")
+		}
+
+		o.base64 = enc
+		return nil
+	}
+}
+
+// WithIndexedKeys informs the parser that slice destinations should be collected from numbered keys (`<prefix>0`, `<prefix>1`, ...) rather than split on a separator.
+// Collection stops at the first unset index. When `<prefix>0` is unset and the loader supports key discovery (see WithKeyLister), every key with the prefix
+// is collected instead, ordered lexically by suffix, e.g. `ITEM_a`, `ITEM_b`.
+//
+// Map destinations are assembled from every key with the prefix, keyed by suffix, so `LIMIT_api=10` yields the entry `api: 10`. This requires key discovery;
+// with a loader that does not support it, parsing a map fails with ErrKeyDiscoveryUnsupported.
+//
+// If no indexed keys are set, the env var itself is parsed as usual.
+func WithIndexedKeys(prefix string) EnvParseOption {
+	return func(o *envParseOpts) error {
+		if prefix == "" {
+			return errors.New("indexed key prefix cannot be empty string")
+		}
+
+		o.indexedPrefix = prefix
+		return nil
+	}
+}
+
+// WithRequireUnit informs the parser that durations must always carry an explicit unit, rejecting even a bare `0`.
+// Bare non-zero numbers are always rejected with a hint suggesting the intended unit.
+func WithRequireUnit() EnvParseOption {
+	return func(o *envParseOpts) error {
+		o.requireUnit = true
+		return nil
+	}
+}
+
+// WithSaturation informs the parser that integer values (including items of slices and maps) too large or small for the destination type should saturate
+// at its max or min rather than fail with an *OverflowError.
+func WithSaturation() EnvParseOption {
+	return func(o *envParseOpts) error {
+		o.saturate = true
+		return nil
+	}
+}
+
+// WithStrictBool informs the parser that bools (including items of slices and maps) must be spelled `true` or `false`, rejecting the other forms
+// accepted by strconv.ParseBool such as `1`, `T` or `TRUE`.
+func WithStrictBool() EnvParseOption {
+	return func(o *envParseOpts) error {
+		o.strictBool = true
+		return nil
+	}
+}
+
+// Strict bundles the most conservative parsing posture, for security-sensitive services: env vars are required, parse failures never fall back to
+// the default, bools must be `true` or `false`, durations must carry a unit, strings must be valid UTF-8, and Unmarshal and Reparse report keys
+// within a namespace or reparse prefix that no field is tagged with as ErrUnknownKey, given a loader supporting key discovery. Options applied
+// after it can relax individual settings, e.g. WithFallbackToDefaultOnError(true).
+//
+// Out of range values fail unless opted into saturation, clamping or rounding, which Strict leaves as configured wherever it is placed.
+func Strict() EnvParseOption {
+	return func(o *envParseOpts) error {
+		o.required, o.defaultOnError, o.strictBool, o.requireUnit, o.validUTF8 = true, false, true, true, true
+		o.unknownKeys = true
+		return nil
+	}
+}
+
+// WithRequired informs the parser that the env var must be set, returning a *MissingError rather than falling back to the default when it is not.
+func WithRequired() EnvParseOption {
+	return func(o *envParseOpts) error {
+		o.required = true
+		return nil
+	}
+}
+
+// WithHint attaches a remediation hint to parse failures, e.g. where a valid value can be found. It is appended to error messages and reported in Errors.JSON.
+func WithHint(hint string) EnvParseOption {
+	return func(o *envParseOpts) error {
+		if hint == "" {
+			return errors.New("hint cannot be empty string")
+		}
+
+		o.hint = hint
+		return nil
+	}
+}
@@ -0,0 +1,498 @@
+package env_test
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ndisidore/go-env"
+)
+
+// TestConcurrentParsing is intended to be run with -race. It replaces the package-wide defaults, so it must not run in parallel with other tests.
+func TestConcurrentParsing(t *testing.T) {
+	t.Cleanup(func() {
+		if err := env.SetDefaultOptions(); err != nil {
+			t.Logf("failed to restore the default options: %v", err)
+			t.Fail()
+		}
+	})
+
+	loader := func(key string) string {
+		return map[string]string{"WHEN": "Fri, 01 Jan 2021 00:00:00 UTC", "LIST": "a;b"}[key]
+	}
+	p, err := env.NewBuilder().With(env.WithEnvLoader(loader)).Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			_, _ = env.FromEnvOrDefault(context.Background(), "WHEN", time.Time{}, env.WithEnvLoader(loader), env.WithTimeLayout(time.RFC1123))
+		}()
+		go func() {
+			defer wg.Done()
+			_, _ = env.GetOrDefault(context.Background(), p, "LIST", []string{}, env.WithEnvParseSeparator(";"))
+		}()
+		go func() {
+			defer wg.Done()
+			if err := env.SetDefaultOptions(env.WithEnvParseSeparator(","), env.WithTimeLayout(time.RFC3339)); err != nil {
+				t.Logf("unexpected error: %v", err)
+				t.Fail()
+			}
+		}()
+	}
+	wg.Wait()
+
+	ret, err := env.FromEnvOrDefault(context.Background(), "LIST", []string{}, env.WithEnvLoader(loader))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(ret) != 1 {
+		t.Logf("per-call options leaked into the package defaults: %v", ret)
+		t.Fail()
+	}
+}
+
+func TestWithBlankIsUnset(t *testing.T) {
+	t.Parallel()
+
+	loader := func(key string) string {
+		return map[string]string{"BLANK": " \t"}[key]
+	}
+	cases := []struct {
+		options             []env.EnvParseOption
+		expected            int
+		expectedErrContains string
+	}{
+		{options: []env.EnvParseOption{env.WithBlankIsUnset()}, expected: 7},
+		{expectedErrContains: "invalid syntax"},
+	}
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			ret, err := env.FromEnvOrDefault(context.Background(), "BLANK", 7, append(tt.options, env.WithEnvLoader(loader))...)
+			switch {
+			case err != nil && tt.expectedErrContains != "":
+				if !strings.Contains(err.Error(), tt.expectedErrContains) {
+					t.Logf("unexpected error: %v", err)
+					t.Fail()
+				}
+			case err != nil:
+				t.Logf("unexpected error: %v", err)
+				t.Fail()
+			case ret != tt.expected:
+				t.Logf("return value (%d) does not match expected (%d)", ret, tt.expected)
+				t.Fail()
+			}
+		})
+	}
+}
+
+func TestWithEmptyIsSet(t *testing.T) {
+	t.Parallel()
+
+	vals := map[string]string{"EMPTY": "", "OLD_EMPTY": "fallback"}
+	lookuper := func(key string) (string, bool) {
+		val, ok := vals[key]
+		return val, ok
+	}
+	cases := []struct {
+		searchEnv string
+		options   []env.EnvParseOption
+		expected  string
+	}{
+		{searchEnv: "EMPTY", options: []env.EnvParseOption{env.WithEmptyIsSet(true)}, expected: ""},
+		{searchEnv: "EMPTY", expected: "default"},
+		{searchEnv: "UNSET", options: []env.EnvParseOption{env.WithEmptyIsSet(true)}, expected: "default"},
+		{searchEnv: "EMPTY", options: []env.EnvParseOption{env.WithEmptyIsSet(true), env.WithRequired()}, expected: ""},
+		{searchEnv: "EMPTY", options: []env.EnvParseOption{env.WithEmptyIsSet(true), env.WithDeprecatedKey("OLD_EMPTY")}, expected: ""},
+		{searchEnv: "EMPTY", options: []env.EnvParseOption{env.WithEmptyIsSet(true), env.WithEnvLoader(func(key string) string { return vals[key] })}, expected: "default"},
+	}
+	for _, tt := range cases {
+		t.Run(tt.searchEnv, func(t *testing.T) {
+			ret, err := env.FromEnvOrDefault(context.Background(), tt.searchEnv, "default", append([]env.EnvParseOption{env.WithEnvLookuper(lookuper)}, tt.options...)...)
+			switch {
+			case err != nil:
+				t.Logf("unexpected error: %v", err)
+				t.Fail()
+			case ret != tt.expected:
+				t.Logf("return value (%q) does not match expected (%q)", ret, tt.expected)
+				t.Fail()
+			}
+		})
+	}
+
+	if ret, err := env.FromEnvOrDefault(context.Background(), "EMPTY", []string{"a"}, env.WithEnvLookuper(lookuper), env.WithEmptyIsSet(true)); err != nil || ret == nil || len(ret) != 0 {
+		t.Logf("explicitly empty list should be empty, got (%q, %v)", ret, err)
+		t.Fail()
+	}
+	if _, err := env.FromEnvOrDefault(context.Background(), "EMPTY", 7, env.WithEnvLookuper(lookuper), env.WithEmptyIsSet(true)); err == nil {
+		t.Log("expected an explicitly empty int to fail to parse")
+		t.Fail()
+	}
+	p, err := env.NewParser(env.WithEnvLookuper(lookuper), env.WithEmptyIsSet(true))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ret, err := env.GetOrDefault(context.Background(), p.WithOverlay(map[string]string{"OVERLAID": ""}), "OVERLAID", "default"); err != nil || ret != "" {
+		t.Logf("explicitly empty overlay should be used, got (%q, %v)", ret, err)
+		t.Fail()
+	}
+}
+
+func TestWithDefaultFunc(t *testing.T) {
+	t.Parallel()
+
+	loader := func(key string) string {
+		return map[string]string{"PORT": "8080", "BAD_PORT": "http"}[key]
+	}
+	calls := 0
+	computed := env.WithDefaultFunc(func() (int, error) {
+		calls++
+		return 9000, nil
+	})
+	cases := []struct {
+		searchEnv string
+		options   []env.EnvParseOption
+		expected  int
+	}{
+		{searchEnv: "PORT", expected: 8080},
+		{searchEnv: "UNSET", expected: 9000},
+		{searchEnv: "BAD_PORT", options: []env.EnvParseOption{env.WithFallbackToDefaultOnError(true)}, expected: 9000},
+	}
+	for _, tt := range cases {
+		ret, err := env.FromEnvOrDefault(context.Background(), tt.searchEnv, 7, append(tt.options, env.WithEnvLoader(loader), computed)...)
+		switch {
+		case err != nil:
+			t.Logf("unexpected error: %v", err)
+			t.Fail()
+		case ret != tt.expected:
+			t.Logf("return value (%d) does not match expected (%d)", ret, tt.expected)
+			t.Fail()
+		}
+	}
+	if calls != 2 {
+		t.Logf("default func should only be called when the default is needed, called %d times", calls)
+		t.Fail()
+	}
+
+	if ret, err := env.FromEnvOrDefault(context.Background(), "UNSET", "fallback", env.WithEnvLoader(loader), computed); err != nil || ret != "fallback" {
+		t.Logf("default func of another type should not apply, got (%q, %v)", ret, err)
+		t.Fail()
+	}
+	failing := env.WithDefaultFunc(func() (int, error) { return 0, errors.New("no cgroup") })
+	if _, err := env.FromEnvOrDefault(context.Background(), "UNSET", 7, env.WithEnvLoader(loader), failing); err == nil || !strings.Contains(err.Error(), "failed to compute default for UNSET: no cgroup") {
+		t.Logf("unexpected error: %v", err)
+		t.Fail()
+	}
+}
+
+func TestWithNormalization(t *testing.T) {
+	t.Parallel()
+
+	loader := func(key string) string {
+		return map[string]string{"BOM_INT": "\uFEFF42", "CRLF_INT": "42\r", "CRLF_STR": "value\r"}[key]
+	}
+	cases := []struct {
+		searchEnv           string
+		options             []env.EnvParseOption
+		expected            int
+		expectedErrContains string
+	}{
+		{searchEnv: "BOM_INT", expected: 42},
+		{searchEnv: "CRLF_INT", expected: 42},
+		{searchEnv: "CRLF_INT", options: []env.EnvParseOption{env.WithNormalization(false)}, expectedErrContains: "invalid syntax"},
+	}
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			ret, err := env.FromEnvOrDefault(context.Background(), tt.searchEnv, 0, append(tt.options, env.WithEnvLoader(loader))...)
+			switch {
+			case err != nil && tt.expectedErrContains != "":
+				if !strings.Contains(err.Error(), tt.expectedErrContains) {
+					t.Logf("unexpected error: %v", err)
+					t.Fail()
+				}
+			case err != nil:
+				t.Logf("unexpected error: %v", err)
+				t.Fail()
+			case ret != tt.expected:
+				t.Logf("return value (%d) does not match expected (%d)", ret, tt.expected)
+				t.Fail()
+			}
+		})
+	}
+
+	str, err := env.FromEnvOrDefault(context.Background(), "CRLF_STR", "", env.WithEnvLoader(loader))
+	if err != nil || str != "value" {
+		t.Logf("return value (%q, %v) does not match expected (value)", str, err)
+		t.Fail()
+	}
+}
+
+func TestWithValidUTF8(t *testing.T) {
+	t.Parallel()
+
+	loader := func(key string) string {
+		return map[string]string{"INVALID": "ab\xffcd", "INVALID_LIST": "a,b\xff"}[key]
+	}
+	if _, err := env.FromEnvOrDefault(context.Background(), "INVALID", "", env.WithEnvLoader(loader)); err != nil {
+		t.Logf("unexpected error without validation: %v", err)
+		t.Fail()
+	}
+	if _, err := env.FromEnvOrDefault(context.Background(), "INVALID", "", env.WithEnvLoader(loader), env.WithValidUTF8()); err == nil || !strings.Contains(err.Error(), "not valid UTF-8") {
+		t.Logf("unexpected error: %v", err)
+		t.Fail()
+	}
+	if _, err := env.FromEnvOrDefault(context.Background(), "INVALID_LIST", []string{}, env.WithEnvLoader(loader), env.WithValidUTF8()); err == nil || !strings.Contains(err.Error(), "pos: 1") {
+		t.Logf("unexpected error: %v", err)
+		t.Fail()
+	}
+}
+
+func TestWithRawBytes(t *testing.T) {
+	t.Parallel()
+
+	const raw = "\uFEFF\x00binary\r"
+	loader := func(key string) string {
+		return map[string]string{"RAW": raw}[key]
+	}
+	cases := []struct {
+		options  []env.EnvParseOption
+		expected string
+	}{
+		{expected: "\x00binary"},
+		{options: []env.EnvParseOption{env.WithRawBytes()}, expected: raw},
+	}
+	for _, tt := range cases {
+		ret, err := env.FromEnvOrDefault(context.Background(), "RAW", []byte{}, append(tt.options, env.WithEnvLoader(loader))...)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(ret) != tt.expected {
+			t.Logf("return value (%q) does not match expected (%q)", ret, tt.expected)
+			t.Fail()
+		}
+	}
+}
+
+func TestWithIndexedKeys(t *testing.T) {
+	t.Parallel()
+
+	loader := func(key string) string {
+		return map[string]string{"ITEM_0": "a,b", "ITEM_1": "c", "ITEM_3": "skipped", "PORT_0": "80", "PORT_1": "x", "LIST": "d,e"}[key]
+	}
+	ret, err := env.FromEnvOrDefault(context.Background(), "ITEM", []string{}, env.WithEnvLoader(loader), env.WithIndexedKeys("ITEM_"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(ret) != 2 || ret[0] != "a,b" || ret[1] != "c" {
+		t.Logf("return value (%v) does not match expected ([a,b c])", ret)
+		t.Fail()
+	}
+
+	ret, err = env.FromEnvOrDefault(context.Background(), "LIST", []string{}, env.WithEnvLoader(loader), env.WithIndexedKeys("LIST_"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(ret) != 2 {
+		t.Logf("return value (%v) does not fall back to the separated env var", ret)
+		t.Fail()
+	}
+
+	if _, err := env.FromEnvOrDefault(context.Background(), "PORT", []int{}, env.WithEnvLoader(loader), env.WithIndexedKeys("PORT_")); err == nil || !strings.Contains(err.Error(), "pos: 1") {
+		t.Logf("unexpected error: %v", err)
+		t.Fail()
+	}
+
+	named := map[string]string{"ZONE_b": "us-west", "ZONE_a": "us-east", "LIMIT_api": "10", "LIMIT_web": "20", "BAD_LIMIT_api": "x"}
+	lister := env.WithKeyLister(func() []string {
+		keys := make([]string, 0, len(named))
+		for k := range named {
+			keys = append(keys, k)
+		}
+		return keys
+	})
+	namedLoader := env.WithEnvLoader(func(key string) string { return named[key] })
+	if zones, err := env.FromEnvOrDefault(context.Background(), "ZONE", []string{}, namedLoader, lister, env.WithIndexedKeys("ZONE_")); err != nil || !reflect.DeepEqual(zones, []string{"us-east", "us-west"}) {
+		t.Logf("FromEnvOrDefault returned (%v, %v), expected suffixes in lexical order", zones, err)
+		t.Fail()
+	}
+	if limits, err := env.FromEnvOrDefault(context.Background(), "LIMIT", map[string]int{}, namedLoader, lister, env.WithIndexedKeys("LIMIT_")); err != nil || !reflect.DeepEqual(limits, map[string]int{"api": 10, "web": 20}) {
+		t.Logf("FromEnvOrDefault returned (%v, %v)", limits, err)
+		t.Fail()
+	}
+	if _, err := env.FromEnvOrDefault(context.Background(), "BAD_LIMIT", map[string]int{}, namedLoader, lister, env.WithIndexedKeys("BAD_LIMIT_")); err == nil || !strings.Contains(err.Error(), "key api failed to parse") {
+		t.Logf("unexpected error: %v", err)
+		t.Fail()
+	}
+	if _, err := env.FromEnvOrDefault(context.Background(), "LIMIT", map[string]int{}, namedLoader, env.WithIndexedKeys("LIMIT_")); !errors.Is(err, env.ErrKeyDiscoveryUnsupported) {
+		t.Logf("expected map destinations to require key discovery, got %v", err)
+		t.Fail()
+	}
+}
+
+func TestWithRequireUnit(t *testing.T) {
+	t.Parallel()
+
+	loader := func(key string) string {
+		return map[string]string{"BARE": "30", "ZERO": "0", "UNIT": "30s"}[key]
+	}
+	cases := []struct {
+		searchEnv           string
+		options             []env.EnvParseOption
+		expected            time.Duration
+		expectedErrContains string
+	}{
+		{searchEnv: "BARE", expectedErrContains: "did you mean 30s or 30m?"},
+		{searchEnv: "ZERO", expected: 0},
+		{searchEnv: "ZERO", options: []env.EnvParseOption{env.WithRequireUnit()}, expectedErrContains: "missing unit"},
+		{searchEnv: "UNIT", options: []env.EnvParseOption{env.WithRequireUnit()}, expected: 30 * time.Second},
+	}
+	for _, tt := range cases {
+		ret, err := env.FromEnvOrDefault(context.Background(), tt.searchEnv, time.Minute, append(tt.options, env.WithEnvLoader(loader))...)
+		switch {
+		case err != nil && tt.expectedErrContains != "":
+			if !strings.Contains(err.Error(), tt.expectedErrContains) {
+				t.Logf("unexpected error: %v", err)
+				t.Fail()
+			}
+		case err != nil:
+			t.Logf("unexpected error: %v", err)
+			t.Fail()
+		case tt.expectedErrContains != "":
+			t.Logf("expected error containing %q, got %s", tt.expectedErrContains, ret)
+			t.Fail()
+		case ret != tt.expected:
+			t.Logf("return value (%s) does not match expected (%s)", ret, tt.expected)
+			t.Fail()
+		}
+	}
+}
+
+func TestWithRequired(t *testing.T) {
+	t.Parallel()
+
+	loader := func(key string) string {
+		return map[string]string{"PORT": "8080"}[key]
+	}
+	ret, err := env.FromEnvOrDefault(context.Background(), "PORT", 0, env.WithEnvLoader(loader), env.WithRequired())
+	if err != nil || ret != 8080 {
+		t.Logf("FromEnvOrDefault returned (%d, %v)", ret, err)
+		t.Fail()
+	}
+
+	_, err = env.FromEnvOrDefault(context.Background(), "HOST", "localhost", env.WithEnvLoader(loader), env.WithRequired())
+	var me *env.MissingError
+	if !errors.As(err, &me) || me.EnvVar != "HOST" {
+		t.Logf("unexpected error: %v", err)
+		t.Fail()
+	}
+}
+
+func TestWithBase64(t *testing.T) {
+	t.Parallel()
+
+	loader := func(key string) string {
+		return map[string]string{"STD": "aGk/Pz8=", "URL": "aGk_Pz8", "BAD": "***"}[key]
+	}
+	cases := []struct {
+		searchEnv           string
+		encoding            *base64.Encoding
+		expected            string
+		expectedErrContains string
+	}{
+		{searchEnv: "STD", encoding: base64.StdEncoding, expected: "hi???"},
+		{searchEnv: "URL", encoding: base64.RawURLEncoding, expected: "hi???"},
+		{searchEnv: "BAD", encoding: base64.StdEncoding, expectedErrContains: "illegal base64 data"},
+	}
+	for _, tt := range cases {
+		ret, err := env.FromEnvOrDefault(context.Background(), tt.searchEnv, []byte{}, env.WithEnvLoader(loader), env.WithBase64(tt.encoding))
+		switch {
+		case err != nil && tt.expectedErrContains != "":
+			if !strings.Contains(err.Error(), tt.expectedErrContains) {
+				t.Logf("unexpected error: %v", err)
+				t.Fail()
+			}
+		case err != nil:
+			t.Logf("unexpected error: %v", err)
+			t.Fail()
+		case string(ret) != tt.expected:
+			t.Logf("return value (%q) does not match expected (%q)", ret, tt.expected)
+			t.Fail()
+		}
+	}
+}
+
+func TestStrict(t *testing.T) {
+	t.Parallel()
+
+	loader := func(key string) string {
+		return map[string]string{"DEBUG": "true", "VERBOSE": "1", "FLAGS": "true,T", "TIMEOUT": "30", "PORT": "70000", "BAD_PORT": "x"}[key]
+	}
+	ctx := context.Background()
+
+	if ret, err := env.FromEnvOrDefault(ctx, "DEBUG", false, env.WithEnvLoader(loader), env.Strict()); err != nil || !ret {
+		t.Logf("FromEnvOrDefault returned (%t, %v)", ret, err)
+		t.Fail()
+	}
+	if ret, err := env.FromEnvOrDefault(ctx, "VERBOSE", false, env.WithEnvLoader(loader)); err != nil || !ret {
+		t.Logf("expected 1 to be accepted outside strict mode, got (%t, %v)", ret, err)
+		t.Fail()
+	}
+
+	cases := []struct {
+		name                string
+		parse               func(opts ...env.EnvParseOption) error
+		expectedErrContains string
+	}{
+		{name: "lenient bool", parse: func(opts ...env.EnvParseOption) error {
+			_, err := env.FromEnvOrDefault(ctx, "VERBOSE", false, opts...)
+			return err
+		}, expectedErrContains: `invalid bool "1" (want true or false)`},
+		{name: "lenient bool item", parse: func(opts ...env.EnvParseOption) error {
+			_, err := env.FromEnvOrDefault(ctx, "FLAGS", []bool{}, opts...)
+			return err
+		}, expectedErrContains: "item T (pos: 1) failed to parse"},
+		{name: "unset", parse: func(opts ...env.EnvParseOption) error {
+			_, err := env.FromEnvOrDefault(ctx, "UNSET", "default", opts...)
+			return err
+		}, expectedErrContains: "UNSET"},
+		{name: "bare duration", parse: func(opts ...env.EnvParseOption) error {
+			_, err := env.FromEnvOrDefault(ctx, "TIMEOUT", time.Second, opts...)
+			return err
+		}, expectedErrContains: "TIMEOUT"},
+		{name: "out of range", parse: func(opts ...env.EnvParseOption) error {
+			_, err := env.FromEnvOrDefault(ctx, "PORT", uint16(0), opts...)
+			return err
+		}, expectedErrContains: "PORT"},
+		{name: "default on error", parse: func(opts ...env.EnvParseOption) error {
+			_, err := env.FromEnvOrDefault(ctx, "BAD_PORT", 0, append([]env.EnvParseOption{env.WithFallbackToDefaultOnError(true)}, opts...)...)
+			return err
+		}, expectedErrContains: "BAD_PORT"},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if err := tt.parse(env.WithEnvLoader(loader), env.Strict()); err == nil || !strings.Contains(err.Error(), tt.expectedErrContains) {
+				t.Logf("unexpected error: %v", err)
+				t.Fail()
+			}
+		})
+	}
+
+	if ret, err := env.FromEnvOrDefault(ctx, "BAD_PORT", 8080, env.WithEnvLoader(loader), env.Strict(), env.WithFallbackToDefaultOnError(true)); err != nil || ret != 8080 {
+		t.Logf("expected later options to relax strict mode, got (%d, %v)", ret, err)
+		t.Fail()
+	}
+	if ret, err := env.FromEnvOrDefault(ctx, "PORT", uint16(0), env.WithEnvLoader(loader), env.WithSaturation(), env.Strict()); err != nil || ret != 65535 {
+		t.Logf("expected saturation to be kept by strict mode wherever it is placed, got (%d, %v)", ret, err)
+		t.Fail()
+	}
+}
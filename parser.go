@@ -2,32 +2,55 @@ package env
 
 import (
 	"context"
+	"encoding"
+	"encoding/base64"
+	"errors"
+	"flag"
 	"fmt"
+	"io/fs"
 	"log/slog"
+	"net"
+	"net/mail"
+	"net/netip"
 	"net/url"
-	"os"
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 )
 
 type (
-	// Parseable represents the types the parser is capable of handling.
+	// Parseable represents the types the parser handles natively, and so the destinations accepted by FromEnvOrDefault and MustFromEnvOrDefault.
+	//
+	// Destinations of any other type are parsed via FromEnvOrDefaultAny. They select an implementation registered via RegisterImplementation if there
+	// is one, and are otherwise parsed via their encoding.TextUnmarshaler, encoding.BinaryUnmarshaler or flag.Value implementation, in that order.
 	Parseable interface {
-		string | bool | int | uint | int64 | uint64 | float64 | time.Duration | time.Time | url.URL | []string | []bool | []int | []uint | []int64 | []uint64 | []float64 | []time.Duration | []time.Time | []url.URL
+		string | bool | int | uint | int64 | uint64 | int8 | int16 | int32 | uint8 | uint16 | uint32 | float32 | float64 | time.Duration | time.Time | url.URL | []string | []bool | []int | []uint | []int64 | []uint64 | []int8 | []int16 | []int32 | []uint16 | []uint32 | []float32 | []float64 | []time.Duration | []time.Time | []url.URL | []byte |
+			net.IP | netip.Addr | netip.Prefix | *net.IPNet | []net.IP | []netip.Addr | []netip.Prefix | []*net.IPNet | mail.Address | []mail.Address | slog.Level | Date | []Date | TimeOfDay | []TimeOfDay | CountryCode | []CountryCode | fs.FileMode |
+			map[string]string | map[string]bool | map[string]int | map[string]uint | map[string]int64 | map[string]uint64 | map[string]float64 | map[string]time.Duration
 	}
 )
 
 // MustFromEnvOrDefault attempts to parse the environment variable provided. If it is empty or missing, the default value is used.
 //
-// If an error is encountered, depending on whether the `WithFallbackToDefaultOnError` option is provided it will either fallback or fatally log & exit.
+// If an error is encountered, depending on whether the `WithFallbackToDefaultOnError` option is provided it will either fallback or fatally log & exit (see WithExitCode and WithJSONFailureRecord).
 func MustFromEnvOrDefault[T Parseable](ctx context.Context, envVar string, defaultVal T, opts ...EnvParseOption) (dest T) {
-	parsed, err := FromEnvOrDefault(ctx, envVar, defaultVal, opts...)
+	return MustFromEnvOrDefaultAny(ctx, envVar, defaultVal, opts...)
+}
+
+// MustFromEnvOrDefaultAny is MustFromEnvOrDefault for destinations that are not Parseable, such as custom types, interfaces with registered
+// implementations, Scheduled and os.Signal. Destinations the parser cannot handle fail at runtime rather than compile time.
+func MustFromEnvOrDefaultAny[T any](ctx context.Context, envVar string, defaultVal T, opts ...EnvParseOption) (dest T) {
+	p, err := NewBuilder().With(opts...).Build()
 	if err != nil {
-		slog.Default().ErrorContext(ctx, "failed to parse env var", slog.String("env_var", envVar), slog.String("error", err.Error()))
-		os.Exit(1)
+		defaults := loadDefaultParseOptions()
+		defaults.fatal(ctx, envVar, err)
 	}
 
+	parsed, err := parse(ctx, &p.opts, envVar, defaultVal)
+	if err != nil {
+		p.opts.fatal(ctx, envVar, err)
+	}
 	return parsed
 }
 
@@ -35,16 +58,144 @@ func MustFromEnvOrDefault[T Parseable](ctx context.Context, envVar string, defau
 //
 // If an error is encountered, depending on whether the `WithFallbackToDefaultOnError` option is provided it will either fallback or return the error back to the client.
 func FromEnvOrDefault[T Parseable](ctx context.Context, envVar string, defaultVal T, opts ...EnvParseOption) (dest T, err error) {
-	parseOpts := &defaultParseOptions
-	for _, opt := range opts {
-		if err := opt(parseOpts); err != nil {
-			return dest, fmt.Errorf("option error: %w", err)
+	return FromEnvOrDefaultAny(ctx, envVar, defaultVal, opts...)
+}
+
+// FromEnvOrDefaultAny is FromEnvOrDefault for destinations that are not Parseable, such as custom types, interfaces with registered implementations,
+// Scheduled and os.Signal. Destinations the parser cannot handle fail at runtime rather than compile time.
+func FromEnvOrDefaultAny[T any](ctx context.Context, envVar string, defaultVal T, opts ...EnvParseOption) (dest T, err error) {
+	p, err := NewBuilder().With(opts...).Build()
+	if err != nil {
+		return dest, err
+	}
+
+	return parse(ctx, &p.opts, envVar, defaultVal)
+}
+
+// FromEnvOrNil attempts to parse the environment variable provided, returning nil if it is empty or missing.
+// This distinguishes a value explicitly configured as the zero value from one that is not configured at all.
+//
+// If a parse error is encountered and the `WithFallbackToDefaultOnError` option is provided, nil is returned in place of the error.
+func FromEnvOrNil[T any](ctx context.Context, envVar string, opts ...EnvParseOption) (*T, error) {
+	p, err := NewBuilder().With(opts...).Build()
+	if err != nil {
+		return nil, err
+	}
+
+	return parseOrNil[T](ctx, &p.opts, envVar)
+}
+
+// parseOrNil parses envVar into a new T, returning nil where parse would have returned the default.
+func parseOrNil[T any](ctx context.Context, parseOpts *envParseOpts, envVar string) (*T, error) {
+	// forcing the value to be required surfaces absence as a MissingError rather than a zero value
+	o := *parseOpts
+	o.required, o.defaultOnError = true, false
+	var zero T
+	v, err := parse(ctx, &o, envVar, zero)
+	if absent, err := parseOpts.absent(parseOpts.namespace+envVar, err); absent || err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// absent classifies err from a parse with required forced on and default on error disabled. It reports whether the value should be treated as unset,
+// i.e. it was missing and not actually required, or failed to parse while WithFallbackToDefaultOnError is set, and otherwise returns err unchanged.
+func (o *envParseOpts) absent(envVar string, err error) (bool, error) {
+	if err == nil {
+		return false, nil
+	}
+	var me *MissingError
+	if errors.As(err, &me) {
+		if o.required {
+			return false, err
 		}
+		return true, nil
+	}
+	if !o.defaultOnError {
+		return false, err
 	}
 
-	envStr := parseOpts.envLoader(envVar)
-	if envStr == "" {
-		return defaultVal, nil
+	var pe *ParseError
+	if errors.As(err, &pe) {
+		err = pe.Err
+	}
+	o.warn(Warning{Kind: WarnDefaultOnError, EnvVar: envVar, Key: envVar, Err: err})
+	return true, nil
+}
+
+func parse[T any](ctx context.Context, parseOpts *envParseOpts, envVar string, defaultVal T) (dest T, err error) {
+	envVar = parseOpts.namespace + envVar
+	if parseOpts.stats != nil {
+		defer parseOpts.stats.record(parseOpts.clock, envVar, parseOpts.clock.Now())
+	}
+	if err := parseOpts.validateKeys(envVar); err != nil {
+		return dest, err
+	}
+
+	_, isBytes := any(dest).([]byte)
+	raw := isBytes && parseOpts.rawBytes
+	envStr := parseOpts.load(envVar, envVar, raw)
+	explicitEmpty := envStr == "" && parseOpts.emptyIsSet && parseOpts.setEmpty(envVar)
+	if envStr == "" && !explicitEmpty {
+		if envStr, err = parseOpts.loadDeprecated(ctx, envVar, raw); err != nil {
+			return dest, err
+		}
+	}
+	if envStr == "" && !explicitEmpty && parseOpts.secretFiles {
+		if envStr, err = parseOpts.loadFile(envVar, raw); err != nil {
+			return dest, err
+		}
+	}
+
+	isList := isListDest(dest)
+	items, entries, indexed := []string(nil), map[string]string(nil), false
+	switch {
+	case parseOpts.indexedPrefix == "":
+	case isList:
+		items = parseOpts.loadIndexed(envVar, parseOpts.indexedPrefix)
+		indexed = len(items) > 0
+	case isMapDest(dest):
+		if entries, err = parseOpts.loadIndexedMap(envVar, parseOpts.indexedPrefix); err != nil {
+			return dest, err
+		}
+		indexed = len(entries) > 0
+		for _, val := range entries {
+			// only for the length check below; map destinations are parsed from entries
+			items = append(items, val)
+		}
+	}
+	if envStr == "" && !indexed && !explicitEmpty {
+		if parseOpts.required {
+			return dest, &MissingError{EnvVar: envVar, Hint: parseOpts.hint, msg: parseOpts.message(MsgRequired, envVar)}
+		}
+		return defaultValue(parseOpts, envVar, defaultVal)
+	}
+
+	fail := func(err error) (T, error) {
+		if parseOpts.defaultOnError {
+			parseOpts.warn(Warning{Kind: WarnDefaultOnError, EnvVar: envVar, Key: envVar, Err: err})
+			return defaultValue(parseOpts, envVar, defaultVal)
+		}
+		return dest, parseOpts.parseError(envVar, fmt.Sprintf("%T", dest), err)
+	}
+	if parseOpts.variantKey != nil && envStr != "" {
+		if envStr, err = selectVariant(envStr, parseOpts.separator, envVar+":"+parseOpts.variantKey(ctx)); err != nil {
+			return fail(err)
+		}
+	}
+	if parseOpts.expand && envStr != "" {
+		if envStr, err = parseOpts.expander(envVar).expand(envStr); err != nil {
+			return fail(err)
+		}
+	}
+	if isList && !indexed {
+		items = splitAndTrim(envStr, parseOpts.separator)
+		if envStr == "" {
+			items = []string{}
+		}
+	}
+	if err := parseOpts.checkLength(envStr, items); err != nil {
+		return fail(err)
 	}
 
 	var (
@@ -52,33 +203,84 @@ func FromEnvOrDefault[T Parseable](ctx context.Context, envVar string, defaultVa
 	)
 	switch any(dest).(type) {
 	case string:
+		if parseOpts.validUTF8 && !utf8.ValidString(envStr) {
+			err = errors.New("value is not valid UTF-8")
+		}
 		v = envStr
+	case []byte:
+		if parseOpts.base64 != nil {
+			v, err = parseOpts.base64.DecodeString(envStr)
+		} else {
+			v = []byte(envStr)
+		}
 	case bool:
-		v, err = strconv.ParseBool(envStr)
+		v, err = parseBool(envStr, parseOpts.strictBool)
 	case int:
-		v, err = strconv.Atoi(envStr)
+		v, err = atoi(envStr, parseOpts.saturate)
 	case uint:
-		var i uint64
-		i, err = strconv.ParseUint(envStr, 10, 64)
-		v = uint(i)
+		v, err = parseUnsigned[uint](envStr, strconv.IntSize, parseOpts.saturate)
 	case int64:
-		v, err = strconv.ParseInt(envStr, 10, 64)
+		v, err = parseSigned[int64](envStr, 64, parseOpts.saturate)
 	case uint64:
-		v, err = strconv.ParseUint(envStr, 10, 64)
+		v, err = parseUnsigned[uint64](envStr, 64, parseOpts.saturate)
+	case int8:
+		v, err = parseSigned[int8](envStr, 8, parseOpts.saturate)
+	case int16:
+		v, err = parseSigned[int16](envStr, 16, parseOpts.saturate)
+	case int32:
+		v, err = parseSigned[int32](envStr, 32, parseOpts.saturate)
+	case uint8:
+		v, err = parseUnsigned[uint8](envStr, 8, parseOpts.saturate)
+	case uint16:
+		v, err = parseUnsigned[uint16](envStr, 16, parseOpts.saturate)
+	case uint32:
+		v, err = parseUnsigned[uint32](envStr, 32, parseOpts.saturate)
+	case float32:
+		v, err = parseFloat32(envStr)
 	case float64:
 		v, err = strconv.ParseFloat(envStr, 64)
 	case time.Duration:
-		v, err = time.ParseDuration(envStr)
+		v, err = parseDuration(envStr, parseOpts.requireUnit)
 	case time.Time:
 		v, err = time.Parse(parseOpts.timeLayout, envStr)
 	case url.URL:
 		v, err = url.Parse(envStr)
+	case net.IP:
+		v, err = parseIP(envStr)
+	case netip.Addr:
+		v, err = netip.ParseAddr(envStr)
+	case netip.Prefix:
+		v, err = netip.ParsePrefix(envStr)
+	case *net.IPNet:
+		_, v, err = net.ParseCIDR(envStr)
+	case mail.Address:
+		v, err = parseMailAddress(envStr)
+	case slog.Level:
+		v, err = ParseLevel(envStr)
+	case Date:
+		v, err = ParseDate(envStr)
+	case TimeOfDay:
+		v, err = ParseTimeOfDay(envStr)
+	case CountryCode:
+		v, err = ParseCountryCode(envStr)
+	case fs.FileMode:
+		v, err = parseFileMode(envStr)
 	case []string:
-		v = strings.Split(envStr, parseOpts.separator)
+		vs := items
+		if !indexed && envStr != "" {
+			vs = strings.Split(envStr, parseOpts.separator)
+		}
+		for i, at := range vs {
+			if parseOpts.validUTF8 && !utf8.ValidString(at) {
+				err = fmt.Errorf("item (pos: %d) failed to parse: value is not valid UTF-8", i)
+				break
+			}
+		}
+		v = vs
 	case []bool:
 		vs := make([]bool, 0)
-		for i, at := range splitAndTrim(envStr, parseOpts.separator) {
-			parsed, innerErr := strconv.ParseBool(at)
+		for i, at := range items {
+			parsed, innerErr := parseBool(at, parseOpts.strictBool)
 			if innerErr != nil {
 				err = fmt.Errorf("item %s (pos: %d) failed to parse: %w", at, i, innerErr)
 				break
@@ -88,8 +290,8 @@ func FromEnvOrDefault[T Parseable](ctx context.Context, envVar string, defaultVa
 		v = vs
 	case []int:
 		vs := make([]int, 0)
-		for i, at := range splitAndTrim(envStr, parseOpts.separator) {
-			parsed, innerErr := strconv.Atoi(at)
+		for i, at := range items {
+			parsed, innerErr := atoi(at, parseOpts.saturate)
 			if innerErr != nil {
 				err = fmt.Errorf("item %s (pos: %d) failed to parse: %w", at, i, innerErr)
 				break
@@ -99,19 +301,19 @@ func FromEnvOrDefault[T Parseable](ctx context.Context, envVar string, defaultVa
 		v = vs
 	case []uint:
 		vs := make([]uint, 0)
-		for i, at := range splitAndTrim(envStr, parseOpts.separator) {
-			parsed, innerErr := strconv.ParseUint(at, 10, 64)
+		for i, at := range items {
+			parsed, innerErr := parseUnsigned[uint](at, strconv.IntSize, parseOpts.saturate)
 			if innerErr != nil {
 				err = fmt.Errorf("item %s (pos: %d) failed to parse: %w", at, i, innerErr)
 				break
 			}
-			vs = append(vs, uint(parsed))
+			vs = append(vs, parsed)
 		}
 		v = vs
 	case []int64:
 		vs := make([]int64, 0)
-		for i, at := range splitAndTrim(envStr, parseOpts.separator) {
-			parsed, innerErr := strconv.ParseInt(at, 10, 64)
+		for i, at := range items {
+			parsed, innerErr := parseSigned[int64](at, 64, parseOpts.saturate)
 			if innerErr != nil {
 				err = fmt.Errorf("item %s (pos: %d) failed to parse: %w", at, i, innerErr)
 				break
@@ -121,8 +323,8 @@ func FromEnvOrDefault[T Parseable](ctx context.Context, envVar string, defaultVa
 		v = vs
 	case []uint64:
 		vs := make([]uint64, 0)
-		for i, at := range splitAndTrim(envStr, parseOpts.separator) {
-			parsed, innerErr := strconv.ParseUint(at, 10, 64)
+		for i, at := range items {
+			parsed, innerErr := parseUnsigned[uint64](at, 64, parseOpts.saturate)
 			if innerErr != nil {
 				err = fmt.Errorf("item %s (pos: %d) failed to parse: %w", at, i, innerErr)
 				break
@@ -130,9 +332,21 @@ func FromEnvOrDefault[T Parseable](ctx context.Context, envVar string, defaultVa
 			vs = append(vs, parsed)
 		}
 		v = vs
+	case []int8:
+		v, err = parseItems(items, func(s string) (int8, error) { return parseSigned[int8](s, 8, parseOpts.saturate) })
+	case []int16:
+		v, err = parseItems(items, func(s string) (int16, error) { return parseSigned[int16](s, 16, parseOpts.saturate) })
+	case []int32:
+		v, err = parseItems(items, func(s string) (int32, error) { return parseSigned[int32](s, 32, parseOpts.saturate) })
+	case []uint16:
+		v, err = parseItems(items, func(s string) (uint16, error) { return parseUnsigned[uint16](s, 16, parseOpts.saturate) })
+	case []uint32:
+		v, err = parseItems(items, func(s string) (uint32, error) { return parseUnsigned[uint32](s, 32, parseOpts.saturate) })
+	case []float32:
+		v, err = parseItems(items, parseFloat32)
 	case []float64:
 		vs := make([]float64, 0)
-		for i, at := range splitAndTrim(envStr, parseOpts.separator) {
+		for i, at := range items {
 			parsed, innerErr := strconv.ParseFloat(at, 64)
 			if innerErr != nil {
 				err = fmt.Errorf("item %s (pos: %d) failed to parse: %w", at, i, innerErr)
@@ -143,8 +357,8 @@ func FromEnvOrDefault[T Parseable](ctx context.Context, envVar string, defaultVa
 		v = vs
 	case []time.Duration:
 		vs := make([]time.Duration, 0)
-		for i, at := range splitAndTrim(envStr, parseOpts.separator) {
-			parsed, innerErr := time.ParseDuration(at)
+		for i, at := range items {
+			parsed, innerErr := parseDuration(at, parseOpts.requireUnit)
 			if innerErr != nil {
 				err = fmt.Errorf("item %s (pos: %d) failed to parse: %w", at, i, innerErr)
 				break
@@ -154,7 +368,7 @@ func FromEnvOrDefault[T Parseable](ctx context.Context, envVar string, defaultVa
 		v = vs
 	case []time.Time:
 		vs := make([]time.Time, 0)
-		for i, at := range splitAndTrim(envStr, parseOpts.separator) {
+		for i, at := range items {
 			parsed, innerErr := time.Parse(parseOpts.timeLayout, at)
 			if innerErr != nil {
 				err = fmt.Errorf("item %s (pos: %d) failed to parse: %w", at, i, innerErr)
@@ -165,7 +379,7 @@ func FromEnvOrDefault[T Parseable](ctx context.Context, envVar string, defaultVa
 		v = vs
 	case []url.URL:
 		vs := make([]url.URL, 0)
-		for i, at := range splitAndTrim(envStr, parseOpts.separator) {
+		for i, at := range items {
 			parsed, innerErr := url.Parse(at)
 			if innerErr != nil {
 				err = fmt.Errorf("item %s (pos: %d) failed to parse: %w", at, i, innerErr)
@@ -174,22 +388,352 @@ func FromEnvOrDefault[T Parseable](ctx context.Context, envVar string, defaultVa
 			vs = append(vs, *parsed)
 		}
 		v = vs
+	case []net.IP:
+		v, err = parseItems(items, parseIP)
+	case []netip.Addr:
+		v, err = parseItems(items, netip.ParseAddr)
+	case []netip.Prefix:
+		v, err = parseItems(items, netip.ParsePrefix)
+	case []*net.IPNet:
+		v, err = parseItems(items, func(s string) (*net.IPNet, error) {
+			_, ipNet, err := net.ParseCIDR(s)
+			return ipNet, err
+		})
+	case []mail.Address:
+		v, err = parseItems(items, parseMailAddress)
+	case []Date:
+		v, err = parseItems(items, ParseDate)
+	case []TimeOfDay:
+		v, err = parseItems(items, ParseTimeOfDay)
+	case []CountryCode:
+		v, err = parseItems(items, ParseCountryCode)
+	case map[string]string:
+		v, err = parseMap(envStr, parseOpts.separator, entries, func(s string) (string, error) { return s, nil })
+	case map[string]bool:
+		v, err = parseMap(envStr, parseOpts.separator, entries, func(s string) (bool, error) { return parseBool(s, parseOpts.strictBool) })
+	case map[string]int:
+		v, err = parseMap(envStr, parseOpts.separator, entries, func(s string) (int, error) { return atoi(s, parseOpts.saturate) })
+	case map[string]uint:
+		v, err = parseMap(envStr, parseOpts.separator, entries, func(s string) (uint, error) { return parseUnsigned[uint](s, strconv.IntSize, parseOpts.saturate) })
+	case map[string]int64:
+		v, err = parseMap(envStr, parseOpts.separator, entries, func(s string) (int64, error) { return parseSigned[int64](s, 64, parseOpts.saturate) })
+	case map[string]uint64:
+		v, err = parseMap(envStr, parseOpts.separator, entries, func(s string) (uint64, error) { return parseUnsigned[uint64](s, 64, parseOpts.saturate) })
+	case map[string]float64:
+		v, err = parseMap(envStr, parseOpts.separator, entries, func(s string) (float64, error) { return strconv.ParseFloat(s, 64) })
+	case map[string]time.Duration:
+		v, err = parseMap(envStr, parseOpts.separator, entries, func(s string) (time.Duration, error) { return parseDuration(s, parseOpts.requireUnit) })
+	default:
+		v, err = parseFallback(ctx, parseOpts, envVar, dest, envStr)
+	}
+	if err == nil {
+		v, err = parseOpts.check(envVar, v)
 	}
 	if err != nil {
-		if parseOpts.defaultOnError {
-			return defaultVal, nil
-		}
-
-		return dest, fmt.Errorf("failed to parse env %s to %T: %v", envVar, dest, err)
+		return fail(err)
 	}
 
 	dest, ok := v.(T)
 	if !ok {
-		return dest, fmt.Errorf("failed to cast env %s to %T", envVar, dest)
+		return dest, errors.New(parseOpts.message(MsgCastFailed, envVar, fmt.Sprintf("%T", dest)))
 	}
 	return dest, nil
 }
 
+// defaultValue returns the result of the function provided via WithDefaultFunc when it produces a T, and defaultVal otherwise.
+func defaultValue[T any](parseOpts *envParseOpts, envVar string, defaultVal T) (T, error) {
+	if parseOpts.defaultFunc == nil {
+		return defaultVal, nil
+	}
+	v, err := parseOpts.defaultFunc()
+	if err != nil {
+		return defaultVal, fmt.Errorf("failed to compute default for %s: %w", envVar, err)
+	}
+	if tv, ok := v.(T); ok {
+		return tv, nil
+	}
+	return defaultVal, nil
+}
+
+// parseError wraps err in a ParseError using the configured message catalog.
+func (o *envParseOpts) parseError(envVar, typ string, err error) error {
+	return &ParseError{EnvVar: envVar, Type: typ, Err: err, Hint: o.hint, msg: o.message(MsgParseFailed, envVar, typ, err)}
+}
+
+// parseFallback parses destinations that are not natively Parseable via registered implementations, Scheduled, os.Signal or the interfaces they implement.
+func parseFallback[T any](ctx context.Context, parseOpts *envParseOpts, envVar string, dest T, envStr string) (any, error) {
+	if impl, ok, err := lookupImplementation((*T)(nil), envStr); ok {
+		return impl, err
+	}
+	if sd, ok := any(&dest).(scheduledDest); ok {
+		if err := sd.parseSchedule(ctx, parseOpts, envVar, envStr); err != nil {
+			return nil, err
+		}
+		return dest, nil
+	}
+	if ok, err := parseSignalDest(&dest, envStr); ok {
+		if err != nil {
+			return nil, err
+		}
+		return dest, nil
+	}
+	fresh := dest
+	if err := parseOpts.guardedDecode(ctx, &fresh, envStr, func() { dest = fresh }); err != nil {
+		return nil, err
+	}
+	return dest, nil
+}
+
+// decodeInto decodes envStr into the value ptr points to.
+//
+// encoding.TextUnmarshaler is preferred, followed by encoding.BinaryUnmarshaler (fed base64-decoded input) and finally flag.Value.
+func decodeInto(ptr any, envStr string) error {
+	switch u := ptr.(type) {
+	case encoding.TextUnmarshaler:
+		return u.UnmarshalText([]byte(envStr))
+	case encoding.BinaryUnmarshaler:
+		decoded, err := base64.StdEncoding.DecodeString(envStr)
+		if err != nil {
+			if decoded, err = base64.RawStdEncoding.DecodeString(envStr); err != nil {
+				return fmt.Errorf("invalid base64: %w", err)
+			}
+		}
+		return u.UnmarshalBinary(decoded)
+	case flag.Value:
+		return u.Set(envStr)
+	}
+
+	return fmt.Errorf("unsupported destination type %s", strings.TrimPrefix(fmt.Sprintf("%T", ptr), "*"))
+}
+
+// parseItems runs each list item through parseVal, reporting the offending item on error.
+func parseItems[V any](items []string, parseVal func(string) (V, error)) ([]V, error) {
+	vs := make([]V, 0, len(items))
+	for i, at := range items {
+		parsed, err := parseVal(at)
+		if err != nil {
+			return nil, fmt.Errorf("item %s (pos: %d) failed to parse: %w", at, i, err)
+		}
+		vs = append(vs, parsed)
+	}
+	return vs, nil
+}
+
+// parseBool parses a bool as strconv.ParseBool does, or only from `true` and `false` when strict is set.
+func parseBool(in string, strict bool) (bool, error) {
+	if strict && in != "true" && in != "false" {
+		return false, fmt.Errorf("invalid bool %q (want true or false)", in)
+	}
+	return strconv.ParseBool(in)
+}
+
+// atoi parses an int like strconv.Atoi, reporting out of range values as an *OverflowError, or clamping them when saturate is set.
+func atoi(in string, saturate bool) (int, error) {
+	n, err := strconv.Atoi(in)
+	return overflowed(in, n, err, saturate)
+}
+
+// parseSigned parses a signed integer of the given bit size, so out of range values error rather than truncate, or clamp when saturate is set.
+func parseSigned[I int8 | int16 | int32 | int64](in string, bitSize int, saturate bool) (I, error) {
+	n, err := strconv.ParseInt(in, 10, bitSize)
+	return overflowed(in, I(n), err, saturate)
+}
+
+// parseUnsigned parses an unsigned integer of the given bit size, so out of range values error rather than truncate, or clamp when saturate is set.
+func parseUnsigned[U uint | uint8 | uint16 | uint32 | uint64](in string, bitSize int, saturate bool) (U, error) {
+	n, err := strconv.ParseUint(in, 10, bitSize)
+	return overflowed(in, U(n), err, saturate)
+}
+
+// overflowed turns a strconv range error into an *OverflowError naming the bound that was exceeded. strconv returns that bound as n, so saturating simply
+// drops the error.
+func overflowed[N int | int8 | int16 | int32 | int64 | uint | uint8 | uint16 | uint32 | uint64](in string, n N, err error, saturate bool) (N, error) {
+	if !errors.Is(err, strconv.ErrRange) {
+		return n, err
+	}
+	if saturate {
+		return n, nil
+	}
+	return n, &OverflowError{Value: in, Type: fmt.Sprintf("%T", n), Limit: fmt.Sprint(n), Below: n < 0}
+}
+
+// parseFloat32 parses a float with a bit size of 32, so values beyond the range of a float32 error rather than overflow to infinity.
+func parseFloat32(in string) (float32, error) {
+	f, err := strconv.ParseFloat(in, 32)
+	return float32(f), err
+}
+
+// parseIP wraps net.ParseIP, which reports failure with a nil IP rather than an error.
+func parseIP(in string) (net.IP, error) {
+	ip := net.ParseIP(in)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid IP address %q", in)
+	}
+	return ip, nil
+}
+
+// parseMailAddress wraps mail.ParseAddress, dereferencing the result.
+func parseMailAddress(in string) (mail.Address, error) {
+	addr, err := mail.ParseAddress(in)
+	if err != nil {
+		return mail.Address{}, err
+	}
+	return *addr, nil
+}
+
+// ParseLevel parses a slog.Level by name (e.g. `debug`, `WARN+2`) or as a plain number (e.g. `-4`), as for slog.Level destinations.
+func ParseLevel(in string) (slog.Level, error) {
+	if n, err := strconv.Atoi(in); err == nil {
+		return slog.Level(n), nil
+	}
+	var l slog.Level
+	err := l.UnmarshalText([]byte(in))
+	return l, err
+}
+
+// parseMap parses separated `key=value` pairs, running each value through parseVal. Keys and values are trimmed and later pairs override earlier ones.
+// When entries collected from indexed keys are provided, they are parsed in place of envStr.
+func parseMap[V any](envStr, sep string, entries map[string]string, parseVal func(string) (V, error)) (map[string]V, error) {
+	if len(entries) > 0 {
+		m := make(map[string]V, len(entries))
+		for key, val := range entries {
+			parsed, err := parseVal(strings.TrimSpace(val))
+			if err != nil {
+				return nil, fmt.Errorf("key %s failed to parse: %w", key, err)
+			}
+			m[key] = parsed
+		}
+		return m, nil
+	}
+	if envStr == "" {
+		return map[string]V{}, nil
+	}
+	pairs := splitAndTrim(envStr, sep)
+	m := make(map[string]V, len(pairs))
+	for i, pair := range pairs {
+		key, val, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("item %s (pos: %d) is not a key=value pair", pair, i)
+		}
+		parsed, err := parseVal(strings.TrimSpace(val))
+		if err != nil {
+			return nil, fmt.Errorf("key %s failed to parse: %w", key, err)
+		}
+		m[key] = parsed
+	}
+	return m, nil
+}
+
+// parseDuration wraps time.ParseDuration, adding a hint when the unit is missing. A bare zero is only accepted when units are not required.
+func parseDuration(in string, requireUnit bool) (time.Duration, error) {
+	if _, err := strconv.ParseFloat(in, 64); err == nil {
+		if in == "0" && !requireUnit {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("missing unit in duration %q (did you mean %[1]ss or %[1]sm?)", in)
+	}
+	return time.ParseDuration(in)
+}
+
+// isListDest reports whether dest is a separated list type. A type switch is used rather than reflection to keep the core usable under tinygo.
+func isListDest(dest any) bool {
+	switch dest.(type) {
+	case []string, []bool, []int, []uint, []int64, []uint64, []int8, []int16, []int32, []uint16, []uint32, []float32, []float64, []time.Duration, []time.Time, []url.URL, []net.IP, []netip.Addr, []netip.Prefix, []*net.IPNet, []mail.Address, []Date, []TimeOfDay, []CountryCode:
+		return true
+	default:
+		return false
+	}
+}
+
+// isMapDest reports whether dest is a map natively parsed from `key=value` pairs.
+func isMapDest(dest any) bool {
+	switch dest.(type) {
+	case map[string]string, map[string]bool, map[string]int, map[string]uint, map[string]int64, map[string]uint64, map[string]float64, map[string]time.Duration:
+		return true
+	default:
+		return false
+	}
+}
+
+// load fetches a single value from the configured loader, applying normalization and blank handling unless raw is set.
+// Any adjustment made to the value is reported as a warning against envVar.
+func (o *envParseOpts) load(envVar, key string, raw bool) string {
+	val := o.envLoader(key)
+	if raw {
+		return val
+	}
+	if o.normalize {
+		if normalized := normalize(val); normalized != val {
+			o.warn(Warning{Kind: WarnNormalized, EnvVar: envVar, Key: key})
+			val = normalized
+		}
+	}
+	if o.blankIsUnset && val != "" && strings.TrimSpace(val) == "" {
+		o.warn(Warning{Kind: WarnBlankIsUnset, EnvVar: envVar, Key: key})
+		val = ""
+	}
+	return val
+}
+
+// setEmpty reports whether key is present with an empty value, as opposed to unset. It is always false when the loader cannot report presence.
+func (o *envParseOpts) setEmpty(key string) bool {
+	if o.envLookuper == nil {
+		return false
+	}
+	val, ok := o.envLookuper(key)
+	return ok && val == ""
+}
+
+// loadIndexed collects the values of prefix0, prefix1, ... stopping at the first index that is unset.
+// If prefix0 is unset and the loader supports key discovery, the values of all keys with the prefix are collected in lexical order of their suffix instead,
+// e.g. prefixa, prefixb.
+func (o *envParseOpts) loadIndexed(envVar, prefix string) []string {
+	var vals []string
+	for i := 0; ; i++ {
+		val := o.load(envVar, prefix+strconv.Itoa(i), false)
+		if val == "" {
+			break
+		}
+		vals = append(vals, val)
+	}
+	if len(vals) > 0 || o.keyLister == nil {
+		return vals
+	}
+
+	for _, key := range o.prefixedKeys(prefix) {
+		if val := o.load(envVar, key, false); val != "" {
+			vals = append(vals, val)
+		}
+	}
+	return vals
+}
+
+// loadIndexedMap collects the values of every key with the prefix, keyed by their suffix, for map destinations. It requires a loader supporting key discovery.
+func (o *envParseOpts) loadIndexedMap(envVar, prefix string) (map[string]string, error) {
+	if o.keyLister == nil {
+		return nil, fmt.Errorf("indexed keys for map %s: %w", envVar, ErrKeyDiscoveryUnsupported)
+	}
+
+	entries := make(map[string]string)
+	for _, key := range o.prefixedKeys(prefix) {
+		if val := o.load(envVar, key, false); val != "" {
+			entries[strings.TrimPrefix(key, prefix)] = val
+		}
+	}
+	return entries, nil
+}
+
+// prefixedKeys lists the keys known to the loader that extend prefix, sorted lexically.
+func (o *envParseOpts) prefixedKeys(prefix string) []string {
+	keys := keysWithPrefix(o.sortedKeys(), prefix)
+	if len(keys) > 0 && keys[0] == prefix {
+		// the prefix itself is not an indexed key
+		keys = keys[1:]
+	}
+	return keys
+}
+
 func splitAndTrim(in string, sep string) []string {
 	strs := strings.Split(in, sep)
 	for i, str := range strs {
@@ -197,3 +741,8 @@ func splitAndTrim(in string, sep string) []string {
 	}
 	return strs
 }
+
+// normalize strips a leading UTF-8 byte order mark and any trailing carriage returns.
+func normalize(in string) string {
+	return strings.TrimRight(strings.TrimPrefix(in, "\uFEFF"), "\r")
+}
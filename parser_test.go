@@ -2,8 +2,16 @@ package env_test
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
 	"math/rand"
+	"net"
+	"net/mail"
+	"net/netip"
 	"reflect"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -224,6 +232,91 @@ func TestParsesParseable(t *testing.T) {
 		}
 	})
 
+	t.Run("small integer widths", func(t *testing.T) {
+		loader := makeLoader(map[string]string{"PORT": "8080", "BIG_PORT": "70000", "ID": "-2147483648", "BIG_ID": "2147483648", "TINY": "-129", "PORTS": "80,443", "BAD_PORTS": "80,65536"})
+		if ret, err := env.FromEnvOrDefault(context.Background(), "PORT", uint16(0), env.WithEnvLoader(loader)); err != nil || ret != 8080 {
+			t.Logf("FromEnvOrDefault returned (%d, %v)", ret, err)
+			t.Fail()
+		}
+		if ret, err := env.FromEnvOrDefault(context.Background(), "ID", int32(0), env.WithEnvLoader(loader)); err != nil || ret != -2147483648 {
+			t.Logf("FromEnvOrDefault returned (%d, %v)", ret, err)
+			t.Fail()
+		}
+		if ret, err := env.FromEnvOrDefault(context.Background(), "PORTS", []uint16{}, env.WithEnvLoader(loader)); err != nil || !reflect.DeepEqual(ret, []uint16{80, 443}) {
+			t.Logf("FromEnvOrDefault returned (%v, %v)", ret, err)
+			t.Fail()
+		}
+		for _, err := range []error{
+			func() error {
+				_, err := env.FromEnvOrDefault(context.Background(), "BIG_PORT", uint16(0), env.WithEnvLoader(loader))
+				return err
+			}(),
+			func() error {
+				_, err := env.FromEnvOrDefault(context.Background(), "BIG_ID", int32(0), env.WithEnvLoader(loader))
+				return err
+			}(),
+			func() error {
+				_, err := env.FromEnvOrDefault(context.Background(), "TINY", int8(0), env.WithEnvLoader(loader))
+				return err
+			}(),
+			func() error {
+				_, err := env.FromEnvOrDefault(context.Background(), "BAD_PORTS", []uint16{}, env.WithEnvLoader(loader))
+				return err
+			}(),
+		} {
+			if !errors.Is(err, strconv.ErrRange) {
+				t.Logf("expected an out of range error, got %v", err)
+				t.Fail()
+			}
+		}
+	})
+
+	t.Run("overflow", func(t *testing.T) {
+		loader := makeLoader(map[string]string{"PORT": "70000", "OFFSET": "-200", "IDS": "1,99999999999999999999", "LIMITS": "a=1,b=-99999999999999999999"})
+		var overflow *env.OverflowError
+		if _, err := env.FromEnvOrDefault(context.Background(), "PORT", uint16(0), env.WithEnvLoader(loader)); !errors.As(err, &overflow) ||
+			err.Error() != "failed to parse env PORT to uint16: value 70000 is out of range for uint16 (max 65535)" {
+			t.Logf("expected an overflow error, got %v", err)
+			t.Fail()
+		}
+		if _, err := env.FromEnvOrDefault(context.Background(), "OFFSET", int8(0), env.WithEnvLoader(loader)); !errors.As(err, &overflow) || overflow.Limit != "-128" || !overflow.Below {
+			t.Logf("expected an overflow error below -128, got %v", err)
+			t.Fail()
+		}
+		if ret, err := env.FromEnvOrDefault(context.Background(), "PORT", uint16(0), env.WithEnvLoader(loader), env.WithSaturation()); err != nil || ret != math.MaxUint16 {
+			t.Logf("FromEnvOrDefault returned (%d, %v)", ret, err)
+			t.Fail()
+		}
+		if ret, err := env.FromEnvOrDefault(context.Background(), "IDS", []uint64{}, env.WithEnvLoader(loader), env.WithSaturation()); err != nil || !reflect.DeepEqual(ret, []uint64{1, math.MaxUint64}) {
+			t.Logf("FromEnvOrDefault returned (%v, %v)", ret, err)
+			t.Fail()
+		}
+		if ret, err := env.FromEnvOrDefault(context.Background(), "LIMITS", map[string]int{}, env.WithEnvLoader(loader), env.WithSaturation()); err != nil || ret["b"] != math.MinInt {
+			t.Logf("FromEnvOrDefault returned (%v, %v)", ret, err)
+			t.Fail()
+		}
+	})
+
+	t.Run("float32", func(t *testing.T) {
+		loader := makeLoader(map[string]string{"THRESHOLD": "0.75", "HUGE": "1e39", "WEIGHTS": "0.5,1.25", "BAD_WEIGHTS": "0.5,1e40"})
+		if ret, err := env.FromEnvOrDefault(context.Background(), "THRESHOLD", float32(0), env.WithEnvLoader(loader)); err != nil || ret != 0.75 {
+			t.Logf("FromEnvOrDefault returned (%v, %v)", ret, err)
+			t.Fail()
+		}
+		if ret, err := env.FromEnvOrDefault(context.Background(), "WEIGHTS", []float32{}, env.WithEnvLoader(loader)); err != nil || !reflect.DeepEqual(ret, []float32{0.5, 1.25}) {
+			t.Logf("FromEnvOrDefault returned (%v, %v)", ret, err)
+			t.Fail()
+		}
+		if _, err := env.FromEnvOrDefault(context.Background(), "HUGE", float32(0), env.WithEnvLoader(loader)); !errors.Is(err, strconv.ErrRange) {
+			t.Logf("expected an out of range error, got %v", err)
+			t.Fail()
+		}
+		if _, err := env.FromEnvOrDefault(context.Background(), "BAD_WEIGHTS", []float32{}, env.WithEnvLoader(loader)); !errors.Is(err, strconv.ErrRange) {
+			t.Logf("expected an out of range error, got %v", err)
+			t.Fail()
+		}
+	})
+
 	t.Run("float64", func(t *testing.T) {
 		t.Parallel()
 		var (
@@ -570,4 +663,311 @@ func TestParsesParseable(t *testing.T) {
 			})
 		}
 	})
+
+	t.Run("net.IP", func(t *testing.T) {
+		loader := makeLoader(map[string]string{"KNOWN_IP": "10.0.0.1", "NOT_IP": "10.0.0"})
+		ret, err := env.FromEnvOrDefault(context.Background(), "KNOWN_IP", net.IPv4zero, env.WithEnvLoader(loader))
+		if err != nil || !ret.Equal(net.ParseIP("10.0.0.1")) {
+			t.Logf("FromEnvOrDefault returned (%v, %v)", ret, err)
+			t.Fail()
+		}
+		if _, err := env.FromEnvOrDefault(context.Background(), "NOT_IP", net.IPv4zero, env.WithEnvLoader(loader)); err == nil || !strings.Contains(err.Error(), "invalid IP address") {
+			t.Logf("unexpected error: %v", err)
+			t.Fail()
+		}
+	})
+
+	t.Run("netip.Addr", func(t *testing.T) {
+		loader := makeLoader(map[string]string{"KNOWN_ADDR": "::1"})
+		ret, err := env.FromEnvOrDefault(context.Background(), "KNOWN_ADDR", netip.Addr{}, env.WithEnvLoader(loader))
+		if err != nil || ret != netip.IPv6Loopback() {
+			t.Logf("FromEnvOrDefault returned (%v, %v)", ret, err)
+			t.Fail()
+		}
+	})
+
+	t.Run("*net.IPNet", func(t *testing.T) {
+		loader := makeLoader(map[string]string{"KNOWN_CIDR": "10.1.2.3/8"})
+		ret, err := env.FromEnvOrDefault(context.Background(), "KNOWN_CIDR", (*net.IPNet)(nil), env.WithEnvLoader(loader))
+		if err != nil || ret.String() != "10.0.0.0/8" {
+			t.Logf("FromEnvOrDefault returned (%v, %v)", ret, err)
+			t.Fail()
+		}
+	})
+
+	t.Run("[]netip.Prefix", func(t *testing.T) {
+		var (
+			defaultVal = []netip.Prefix{netip.MustParsePrefix("127.0.0.0/8")}
+			loader     = makeLoader(map[string]string{"KNOWN_CIDRS": "10.0.0.0/8, 192.168.0.0/16", "NOT_CIDRS": "10.0.0.0/8,192.168.0.0"})
+			cases      = []struct {
+				searchEnv           string
+				expected            []netip.Prefix
+				expectedErrContains string
+			}{
+				{searchEnv: "KNOWN_CIDRS", expected: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("192.168.0.0/16")}},
+				{searchEnv: "UNKNOWN_ENV", expected: defaultVal},
+				{searchEnv: "NOT_CIDRS", expectedErrContains: "item 192.168.0.0 (pos: 1) failed to parse"},
+			}
+		)
+		for _, tt := range cases {
+			t.Run("", func(t *testing.T) {
+				ret, err := env.FromEnvOrDefault(context.Background(), tt.searchEnv, defaultVal, env.WithEnvLoader(loader))
+				switch {
+				case err != nil && tt.expectedErrContains != "":
+					if !strings.Contains(err.Error(), tt.expectedErrContains) {
+						t.Logf("unexpected error: %v", err)
+						t.Fail()
+					}
+				case err != nil:
+					t.Logf("unexpected error: %v", err)
+					t.Fail()
+				case !reflect.DeepEqual(ret, tt.expected):
+					t.Logf("return value (%v) does not match expected (%v)", ret, tt.expected)
+					t.Fail()
+				}
+			})
+		}
+	})
+
+	t.Run("[]mail.Address", func(t *testing.T) {
+		loader := makeLoader(map[string]string{"KNOWN_RECIPIENTS": "a@x.com, Bob <b@y.com>", "NOT_RECIPIENTS": "a@x.com,not-an-address"})
+		ret, err := env.FromEnvOrDefault(context.Background(), "KNOWN_RECIPIENTS", []mail.Address{}, env.WithEnvLoader(loader))
+		expected := []mail.Address{{Address: "a@x.com"}, {Name: "Bob", Address: "b@y.com"}}
+		if err != nil || !reflect.DeepEqual(ret, expected) {
+			t.Logf("FromEnvOrDefault returned (%v, %v)", ret, err)
+			t.Fail()
+		}
+		if _, err := env.FromEnvOrDefault(context.Background(), "NOT_RECIPIENTS", []mail.Address{}, env.WithEnvLoader(loader)); err == nil || !strings.Contains(err.Error(), "item not-an-address (pos: 1) failed to parse") {
+			t.Logf("unexpected error: %v", err)
+			t.Fail()
+		}
+		if ret, err := env.FromEnvOrDefault(context.Background(), "UNKNOWN_ENV", mail.Address{Address: "ops@x.com"}, env.WithEnvLoader(loader)); err != nil || ret.Address != "ops@x.com" {
+			t.Logf("FromEnvOrDefault returned (%v, %v)", ret, err)
+			t.Fail()
+		}
+	})
+
+	t.Run("slog.Level", func(t *testing.T) {
+		var (
+			loader = makeLoader(map[string]string{"NAMED_LEVEL": "warn", "OFFSET_LEVEL": "DEBUG+2", "NUMERIC_LEVEL": "-8", "NOT_LEVEL": "loud"})
+			cases  = []struct {
+				searchEnv           string
+				expected            slog.Level
+				expectedErrContains string
+			}{
+				{searchEnv: "NAMED_LEVEL", expected: slog.LevelWarn},
+				{searchEnv: "OFFSET_LEVEL", expected: slog.LevelDebug + 2},
+				{searchEnv: "NUMERIC_LEVEL", expected: slog.Level(-8)},
+				{searchEnv: "UNKNOWN_ENV", expected: slog.LevelInfo},
+				{searchEnv: "NOT_LEVEL", expectedErrContains: "unknown name"},
+			}
+		)
+		for _, tt := range cases {
+			t.Run("", func(t *testing.T) {
+				ret, err := env.FromEnvOrDefault(context.Background(), tt.searchEnv, slog.LevelInfo, env.WithEnvLoader(loader))
+				switch {
+				case err != nil && tt.expectedErrContains != "":
+					if !strings.Contains(err.Error(), tt.expectedErrContains) {
+						t.Logf("unexpected error: %v", err)
+						t.Fail()
+					}
+				case err != nil:
+					t.Logf("unexpected error: %v", err)
+					t.Fail()
+				case ret != tt.expected:
+					t.Logf("return value (%s) does not match expected (%s)", ret, tt.expected)
+					t.Fail()
+				}
+			})
+		}
+	})
+
+	t.Run("map[string]time.Duration", func(t *testing.T) {
+		var (
+			defaultVal = map[string]time.Duration{"read": time.Second}
+			loader     = makeLoader(map[string]string{"KNOWN_DURATION_MAP": "read=5s, write = 10s", "NOT_DURATION_MAP": "read=5s,write=abcd", "NOT_PAIR_MAP": "read=5s,write"})
+			cases      = []struct {
+				searchEnv           string
+				expected            map[string]time.Duration
+				expectedErrContains string
+			}{
+				{searchEnv: "KNOWN_DURATION_MAP", expected: map[string]time.Duration{"read": 5 * time.Second, "write": 10 * time.Second}},
+				{searchEnv: "UNKNOWN_ENV", expected: defaultVal},
+				{searchEnv: "NOT_DURATION_MAP", expectedErrContains: "key write failed to parse"},
+				{searchEnv: "NOT_PAIR_MAP", expectedErrContains: "item write (pos: 1) is not a key=value pair"},
+			}
+		)
+		for _, tt := range cases {
+			t.Run("", func(t *testing.T) {
+				ret, err := env.FromEnvOrDefault(context.Background(), tt.searchEnv, defaultVal, env.WithEnvLoader(loader))
+				switch {
+				case err != nil && tt.expectedErrContains != "":
+					if !strings.Contains(err.Error(), tt.expectedErrContains) {
+						t.Logf("unexpected error: %v", err)
+						t.Fail()
+					}
+				case err != nil:
+					t.Logf("unexpected error: %v", err)
+					t.Fail()
+				case !reflect.DeepEqual(ret, tt.expected):
+					t.Logf("return value (%v) does not match expected (%v)", ret, tt.expected)
+					t.Fail()
+				}
+			})
+		}
+	})
+
+	t.Run("map[string]int", func(t *testing.T) {
+		loader := makeLoader(map[string]string{"KNOWN_INT_MAP": "a=1;b=2"})
+		ret, err := env.FromEnvOrDefault(context.Background(), "KNOWN_INT_MAP", map[string]int{}, env.WithEnvLoader(loader), env.WithEnvParseSeparator(";"))
+		if err != nil || !reflect.DeepEqual(ret, map[string]int{"a": 1, "b": 2}) {
+			t.Logf("FromEnvOrDefault returned (%v, %v)", ret, err)
+			t.Fail()
+		}
+	})
+}
+
+type hostPorts []string
+
+func (h *hostPorts) String() string {
+	return strings.Join(*h, ",")
+}
+
+func (h *hostPorts) Set(val string) error {
+	for _, hp := range strings.Split(val, ",") {
+		if !strings.Contains(hp, ":") {
+			return fmt.Errorf("%s is missing a port", hp)
+		}
+		*h = append(*h, hp)
+	}
+	return nil
+}
+
+func TestParsesFlagValue(t *testing.T) {
+	t.Parallel()
+
+	loader := func(key string) string {
+		return map[string]string{"KNOWN_HOSTS": "a:1,b:2", "NOT_HOSTS": "a"}[key]
+	}
+	cases := []struct {
+		searchEnv           string
+		expected            hostPorts
+		expectedErrContains string
+	}{
+		{searchEnv: "KNOWN_HOSTS", expected: hostPorts{"a:1", "b:2"}},
+		{searchEnv: "UNKNOWN_ENV", expected: hostPorts{"default:0"}},
+		{searchEnv: "NOT_HOSTS", expectedErrContains: "missing a port"},
+	}
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			ret, err := env.FromEnvOrDefaultAny(context.Background(), tt.searchEnv, hostPorts{"default:0"}, env.WithEnvLoader(loader))
+			switch {
+			case err != nil && tt.expectedErrContains != "":
+				if !strings.Contains(err.Error(), tt.expectedErrContains) {
+					t.Logf("unexpected error: %v", err)
+					t.Fail()
+				}
+			case err != nil:
+				t.Logf("unexpected error: %v", err)
+				t.Fail()
+			case !reflect.DeepEqual(ret, tt.expected):
+				t.Logf("return value (%v) does not match expected (%v)", ret, tt.expected)
+				t.Fail()
+			}
+		})
+	}
+
+	if _, err := env.FromEnvOrDefaultAny(context.Background(), "KNOWN_HOSTS", struct{}{}, env.WithEnvLoader(loader)); err == nil || !strings.Contains(err.Error(), "unsupported destination type") {
+		t.Logf("unexpected error: %v", err)
+		t.Fail()
+	}
+}
+
+type signingKey struct {
+	id  byte
+	key []byte
+}
+
+func (k *signingKey) UnmarshalBinary(data []byte) error {
+	if len(data) < 2 {
+		return errors.New("key too short")
+	}
+	k.id, k.key = data[0], data[1:]
+	return nil
+}
+
+func TestParsesUnmarshalers(t *testing.T) {
+	t.Parallel()
+
+	loader := func(key string) string {
+		return map[string]string{"ADDR": "127.0.0.1:8080", "KEY": "AQID", "KEY_RAW": "AQIDBA", "SHORT_KEY": "AQ==", "NOT_B64": "!!"}[key]
+	}
+
+	addr, err := env.FromEnvOrDefaultAny(context.Background(), "ADDR", netip.AddrPort{}, env.WithEnvLoader(loader))
+	if err != nil || addr != netip.MustParseAddrPort("127.0.0.1:8080") {
+		t.Logf("text unmarshaler returned (%v, %v)", addr, err)
+		t.Fail()
+	}
+
+	cases := []struct {
+		searchEnv           string
+		expected            signingKey
+		expectedErrContains string
+	}{
+		{searchEnv: "KEY", expected: signingKey{id: 1, key: []byte{2, 3}}},
+		{searchEnv: "KEY_RAW", expected: signingKey{id: 1, key: []byte{2, 3, 4}}},
+		{searchEnv: "SHORT_KEY", expectedErrContains: "key too short"},
+		{searchEnv: "NOT_B64", expectedErrContains: "invalid base64"},
+	}
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			ret, err := env.FromEnvOrDefaultAny(context.Background(), tt.searchEnv, signingKey{}, env.WithEnvLoader(loader))
+			switch {
+			case err != nil && tt.expectedErrContains != "":
+				if !strings.Contains(err.Error(), tt.expectedErrContains) {
+					t.Logf("unexpected error: %v", err)
+					t.Fail()
+				}
+			case err != nil:
+				t.Logf("unexpected error: %v", err)
+				t.Fail()
+			case !reflect.DeepEqual(ret, tt.expected):
+				t.Logf("return value (%v) does not match expected (%v)", ret, tt.expected)
+				t.Fail()
+			}
+		})
+	}
+}
+
+func TestFromEnvOrNil(t *testing.T) {
+	t.Parallel()
+
+	loader := func(key string) string {
+		return map[string]string{"ZERO": "0", "TIMEOUT": "5s", "BAD": "abc"}[key]
+	}
+
+	if ret, err := env.FromEnvOrNil[int](context.Background(), "ZERO", env.WithEnvLoader(loader)); err != nil || ret == nil || *ret != 0 {
+		t.Logf("explicit zero should be returned as a non-nil pointer, got (%v, %v)", ret, err)
+		t.Fail()
+	}
+	if ret, err := env.FromEnvOrNil[time.Duration](context.Background(), "TIMEOUT", env.WithEnvLoader(loader)); err != nil || ret == nil || *ret != 5*time.Second {
+		t.Logf("FromEnvOrNil returned (%v, %v)", ret, err)
+		t.Fail()
+	}
+	if ret, err := env.FromEnvOrNil[bool](context.Background(), "UNSET", env.WithEnvLoader(loader)); err != nil || ret != nil {
+		t.Logf("unset should be returned as nil, got (%v, %v)", ret, err)
+		t.Fail()
+	}
+	if _, err := env.FromEnvOrNil[bool](context.Background(), "UNSET", env.WithEnvLoader(loader), env.WithRequired()); !errors.As(err, new(*env.MissingError)) {
+		t.Logf("expected a missing error, got %v", err)
+		t.Fail()
+	}
+	if _, err := env.FromEnvOrNil[int](context.Background(), "BAD", env.WithEnvLoader(loader)); !errors.As(err, new(*env.ParseError)) {
+		t.Logf("expected a parse error, got %v", err)
+		t.Fail()
+	}
+	if ret, err := env.FromEnvOrNil[int](context.Background(), "BAD", env.WithEnvLoader(loader), env.WithFallbackToDefaultOnError(true)); err != nil || ret != nil {
+		t.Logf("a swallowed parse error should be returned as nil, got (%v, %v)", ret, err)
+		t.Fail()
+	}
 }
@@ -0,0 +1,62 @@
+//go:build !tinygo
+
+package env
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"reflect"
+	"time"
+)
+
+// Poll re-parses the environment variable every interval and emits the value on the returned channel whenever it changes. The current value is always emitted first.
+//
+// Parse errors encountered while polling are logged and the previous value is retained. The channel is closed once the context is done.
+// Values are compared with reflect.DeepEqual, so Poll is not available in tinygo builds.
+func Poll[T any](ctx context.Context, envVar string, defaultVal T, interval time.Duration, opts ...EnvParseOption) (<-chan T, error) {
+	if interval <= 0 {
+		return nil, errors.New("poll interval must be positive")
+	}
+	p, err := NewBuilder().With(opts...).Build()
+	if err != nil {
+		return nil, err
+	}
+	current, err := parse(ctx, &p.opts, envVar, defaultVal)
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan T, 1)
+	ch <- current
+	go func() {
+		defer close(ch)
+		ticker := p.opts.clock.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C():
+			}
+
+			next, err := parse(ctx, &p.opts, envVar, defaultVal)
+			if err != nil {
+				slog.Default().WarnContext(ctx, "failed to poll env var", slog.String("env_var", envVar), slog.String("error", err.Error()))
+				continue
+			}
+			if reflect.DeepEqual(next, current) {
+				continue
+			}
+			current = next
+
+			select {
+			case <-ctx.Done():
+				return
+			case ch <- next:
+			}
+		}
+	}()
+
+	return ch, nil
+}
@@ -0,0 +1,52 @@
+//go:build !tinygo
+
+package env_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ndisidore/go-env"
+)
+
+func TestPoll(t *testing.T) {
+	t.Parallel()
+
+	var val atomic.Value
+	val.Store("1")
+	loader := func(key string) string {
+		return val.Load().(string)
+	}
+	clock := newFakeClock(time.Now())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch, err := env.Poll(ctx, "LEVEL", 0, time.Second, env.WithEnvLoader(loader), env.WithClock(clock))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := <-ch; got != 1 {
+		t.Logf("initial value (%d) does not match expected (1)", got)
+		t.Fail()
+	}
+
+	clock.Tick(time.Second)
+	val.Store("not an int")
+	clock.Tick(time.Second)
+	val.Store("2")
+	clock.Tick(time.Second)
+	if got := <-ch; got != 2 {
+		t.Logf("updated value (%d) does not match expected (2)", got)
+		t.Fail()
+	}
+
+	cancel()
+	for range ch {
+	}
+
+	if _, err := env.Poll(context.Background(), "LEVEL", 0, 0); err == nil {
+		t.Log("expected an error for a non-positive interval")
+		t.Fail()
+	}
+}
@@ -0,0 +1,52 @@
+//go:build !tinygo
+
+package env
+
+import (
+	"context"
+	"errors"
+)
+
+// WithProvenance informs Unmarshal to record the Provenance of each tagged field into dest, keyed by field name (e.g. `Database.Hosts`).
+//
+// dest is written to without synchronization, so this is intended as a per-call option rather than one shared by a Parser.
+func WithProvenance(dest map[string]Provenance) EnvParseOption {
+	return func(o *envParseOpts) error {
+		if dest == nil {
+			return errors.New("provenance map cannot be nil")
+		}
+
+		o.provenance = dest
+		return nil
+	}
+}
+
+// trackProvenance instruments the options to observe the lookups of key, returning a function reporting the field's provenance once parsed.
+func (o *envParseOpts) trackProvenance(ctx context.Context, key string) func() Provenance {
+	prov := Provenance{Key: key}
+	fellBack := false
+
+	base := o.envLoader
+	o.envLoader = func(k string) string {
+		val := base(k)
+		if val != "" && prov.Source == "" {
+			prov.Source, prov.RawLength = k, len(val)
+		}
+		return val
+	}
+	baseHandler := o.warningHandler
+	o.warningHandler = func(w Warning) {
+		fellBack = fellBack || w.Kind == WarnDefaultOnError
+		switch {
+		case baseHandler != nil:
+			baseHandler(w)
+		case w.Kind == WarnDeprecatedKey:
+			logDeprecated(ctx, w.EnvVar, w.Key)
+		}
+	}
+
+	return func() Provenance {
+		prov.DefaultUsed = prov.Source == "" || fellBack
+		return prov
+	}
+}
@@ -0,0 +1,13 @@
+package env
+
+// Provenance records where the value of a struct field populated by Unmarshal came from.
+type Provenance struct {
+	// Key is the env var named by the field's tag.
+	Key string
+	// Source is the key the value was read from, which differs from Key for deprecated and indexed keys. It is empty when no value was found.
+	Source string
+	// RawLength is the length in bytes of the raw value read from Source.
+	RawLength int
+	// DefaultUsed reports whether the field kept its default, either because no value was found or because parsing failed under WithFallbackToDefaultOnError.
+	DefaultUsed bool
+}
@@ -0,0 +1,128 @@
+package env
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type (
+	// Window is a daily time range, given as offsets from midnight in the local time of the instant being checked.
+	// A window whose End is before its Start wraps past midnight, e.g. 22h to 6h.
+	Window struct {
+		Start time.Duration
+		End   time.Duration
+	}
+
+	// Scheduled is a destination for values that change by time of day, e.g. `10@off-peak,50@peak`, where the named windows are provided via WithWindows.
+	// Each value is parsed as a T by the same parser as any other env value. A value without a window applies whenever no window matches.
+	// A trailing `@name` is only treated as a window when name is one provided via WithWindows, so values that themselves contain `@`, such as emails
+	// or URLs with userinfo, are parsed whole.
+	//
+	// Values are separated by the parser's separator, so T cannot itself be a separated list.
+	Scheduled[T any] struct {
+		entries  []scheduledEntry[T]
+		fallback *T
+	}
+
+	scheduledEntry[T any] struct {
+		window Window
+		value  T
+	}
+
+	// scheduledDest is implemented by *Scheduled[T], allowing the parser to populate it without knowing T.
+	scheduledDest interface {
+		parseSchedule(ctx context.Context, parseOpts *envParseOpts, envVar, envStr string) error
+	}
+)
+
+// Contains reports whether the time of day of t falls within the window. Start is inclusive and End exclusive.
+func (w Window) Contains(t time.Time) bool {
+	// the wall clock time rather than the time elapsed since midnight, which differ on days with a DST transition
+	h, m, sec := t.Clock()
+	offset := time.Duration(h)*time.Hour + time.Duration(m)*time.Minute + time.Duration(sec)*time.Second + time.Duration(t.Nanosecond())
+	if w.End < w.Start {
+		return offset >= w.Start || offset < w.End
+	}
+	return offset >= w.Start && offset < w.End
+}
+
+// WithWindows provides the named windows referenced by Scheduled values. Windows are merged with any provided previously.
+func WithWindows(windows map[string]Window) EnvParseOption {
+	return func(o *envParseOpts) error {
+		if windows == nil {
+			return errors.New("windows cannot be nil")
+		}
+
+		merged := make(map[string]Window, len(o.windows)+len(windows))
+		for k, v := range o.windows {
+			merged[k] = v
+		}
+		for k, v := range windows {
+			merged[k] = v
+		}
+		o.windows = merged
+		return nil
+	}
+}
+
+// At returns the value in effect at t: the value of the first window containing t, else the value without a window, else the zero value.
+func (s Scheduled[T]) At(t time.Time) T {
+	for _, e := range s.entries {
+		if e.window.Contains(t) {
+			return e.value
+		}
+	}
+	if s.fallback != nil {
+		return *s.fallback
+	}
+	var zero T
+	return zero
+}
+
+func (s *Scheduled[T]) parseSchedule(ctx context.Context, parseOpts *envParseOpts, envVar, envStr string) error {
+	var parsed Scheduled[T]
+	for i, item := range splitAndTrim(envStr, parseOpts.separator) {
+		val, name, windowed := item, "", false
+		at := strings.LastIndex(item, "@")
+		if at >= 0 {
+			_, windowed = parseOpts.windows[item[at+1:]]
+		}
+		if windowed {
+			val, name = item[:at], item[at+1:]
+		}
+
+		// each value runs through the parser on its own, without the lookup features that only apply to the env var as a whole
+		valOpts := *parseOpts
+		valOpts.envLoader = func(string) string { return val }
+		valOpts.deprecatedKeys, valOpts.indexedPrefix, valOpts.required, valOpts.defaultOnError, valOpts.variantKey, valOpts.stats = nil, "", false, false, nil, nil
+		valOpts.namespace = ""
+		var zero T
+		v, err := parse(ctx, &valOpts, envVar, zero)
+		if err != nil {
+			var pe *ParseError
+			if errors.As(err, &pe) {
+				err = pe.Err
+			}
+			if at >= 0 && !windowed {
+				// most likely a typo in the window name rather than a value containing `@`
+				return fmt.Errorf("item %s (pos: %d) references unknown window %q: %w", item, i, item[at+1:], err)
+			}
+			return fmt.Errorf("item %s (pos: %d) failed to parse: %w", item, i, err)
+		}
+
+		if !windowed {
+			if parsed.fallback != nil {
+				return fmt.Errorf("item %s (pos: %d) is a second value without a window", item, i)
+			}
+			parsed.fallback = &v
+			continue
+		}
+		parsed.entries = append(parsed.entries, scheduledEntry[T]{window: parseOpts.windows[name], value: v})
+	}
+
+	*s = parsed
+	return nil
+}
@@ -0,0 +1,109 @@
+package env_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ndisidore/go-env"
+)
+
+func TestScheduled(t *testing.T) {
+	t.Parallel()
+
+	loader := func(key string) string {
+		return map[string]string{"RATE": "10@off-peak, 50@peak, 25", "BAD_WINDOW": "10@lunch", "BAD_VALUE": "x@peak"}[key]
+	}
+	windows := env.WithWindows(map[string]env.Window{
+		"peak":     {Start: 9 * time.Hour, End: 17 * time.Hour},
+		"off-peak": {Start: 22 * time.Hour, End: 6 * time.Hour},
+	})
+
+	ret, err := env.FromEnvOrDefaultAny(context.Background(), "RATE", env.Scheduled[int]{}, env.WithEnvLoader(loader), windows)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	day := time.Date(2026, time.October, 15, 0, 0, 0, 0, time.UTC)
+	cases := []struct {
+		at       time.Duration
+		expected int
+	}{
+		{at: 23 * time.Hour, expected: 10},
+		{at: 2 * time.Hour, expected: 10},
+		{at: 9 * time.Hour, expected: 50},
+		{at: 17 * time.Hour, expected: 25},
+	}
+	for _, tt := range cases {
+		if got := ret.At(day.Add(tt.at)); got != tt.expected {
+			t.Logf("value at %s (%d) does not match expected (%d)", tt.at, got, tt.expected)
+			t.Fail()
+		}
+	}
+
+	if _, err := env.FromEnvOrDefaultAny(context.Background(), "BAD_WINDOW", env.Scheduled[int]{}, env.WithEnvLoader(loader), windows); err == nil || !strings.Contains(err.Error(), `unknown window "lunch"`) {
+		t.Logf("unexpected error: %v", err)
+		t.Fail()
+	}
+	if _, err := env.FromEnvOrDefaultAny(context.Background(), "BAD_VALUE", env.Scheduled[int]{}, env.WithEnvLoader(loader), windows); err == nil || !strings.Contains(err.Error(), "item x@peak (pos: 0) failed to parse") {
+		t.Logf("unexpected error: %v", err)
+		t.Fail()
+	}
+
+	// values containing `@` are only split on it when it names a window
+	contacts := func(key string) string {
+		return map[string]string{"ONCALL": "night@example.com@off-peak, ops@example.com", "DSN": "postgres://app:pw@db/app"}[key]
+	}
+	oncall, err := env.FromEnvOrDefaultAny(context.Background(), "ONCALL", env.Scheduled[string]{}, env.WithEnvLoader(contacts), windows)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := oncall.At(day.Add(23 * time.Hour)); got != "night@example.com" {
+		t.Logf("value at 23h (%s) does not match expected (night@example.com)", got)
+		t.Fail()
+	}
+	if got := oncall.At(day.Add(12 * time.Hour)); got != "ops@example.com" {
+		t.Logf("value at 12h (%s) does not match expected (ops@example.com)", got)
+		t.Fail()
+	}
+	if dsn, err := env.FromEnvOrDefaultAny(context.Background(), "DSN", env.Scheduled[string]{}, env.WithEnvLoader(contacts), windows); err != nil || dsn.At(day) != "postgres://app:pw@db/app" {
+		t.Logf("FromEnvOrDefault returned (%v, %v)", dsn, err)
+		t.Fail()
+	}
+}
+
+func TestWindowContainsDST(t *testing.T) {
+	t.Parallel()
+
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+	business := env.Window{Start: 9 * time.Hour, End: 17 * time.Hour}
+	overnight := env.Window{Start: 22 * time.Hour, End: 6 * time.Hour}
+	cases := []struct {
+		at       time.Time
+		window   env.Window
+		expected bool
+	}{
+		// clocks spring forward from 02:00 to 03:00 on 2026-03-08
+		{at: time.Date(2026, time.March, 8, 9, 0, 0, 0, loc), window: business, expected: true},
+		{at: time.Date(2026, time.March, 8, 8, 30, 0, 0, loc), window: business, expected: false},
+		{at: time.Date(2026, time.March, 8, 16, 59, 0, 0, loc), window: business, expected: true},
+		{at: time.Date(2026, time.March, 8, 17, 0, 0, 0, loc), window: business, expected: false},
+		{at: time.Date(2026, time.March, 8, 5, 59, 0, 0, loc), window: overnight, expected: true},
+		{at: time.Date(2026, time.March, 8, 6, 0, 0, 0, loc), window: overnight, expected: false},
+		// clocks fall back from 02:00 to 01:00 on 2026-11-01
+		{at: time.Date(2026, time.November, 1, 8, 59, 0, 0, loc), window: business, expected: false},
+		{at: time.Date(2026, time.November, 1, 9, 0, 0, 0, loc), window: business, expected: true},
+		{at: time.Date(2026, time.November, 1, 17, 0, 0, 0, loc), window: business, expected: false},
+		{at: time.Date(2026, time.November, 1, 6, 0, 0, 0, loc), window: overnight, expected: false},
+		{at: time.Date(2026, time.November, 1, 22, 0, 0, 0, loc), window: overnight, expected: true},
+	}
+	for _, tt := range cases {
+		if got := tt.window.Contains(tt.at); got != tt.expected {
+			t.Logf("window %+v contains %s: %t, expected %t", tt.window, tt.at, got, tt.expected)
+			t.Fail()
+		}
+	}
+}
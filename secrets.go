@@ -0,0 +1,69 @@
+package env
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// fileSuffix is appended to a key to name the env var holding the path of a file containing its value, see WithSecretFiles.
+const fileSuffix = "_FILE"
+
+// WithSecretFiles follows the Docker and Kubernetes convention for secrets mounted as files: if the env var is unset but `<KEY>_FILE` names a file,
+// the file's contents, less a single trailing newline, are used as the value. A file that cannot be read fails the parse.
+func WithSecretFiles() EnvParseOption {
+	return func(o *envParseOpts) error {
+		o.secretFiles = true
+		return nil
+	}
+}
+
+// loadFile reads the value of envVar from the file named by `<envVar>_FILE`, if set. The trailing newline is kept when raw is set.
+func (o *envParseOpts) loadFile(envVar string, raw bool) (string, error) {
+	path := o.load(envVar, envVar+fileSuffix, false)
+	if path == "" {
+		return "", nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s%s: %w", envVar, fileSuffix, err)
+	}
+	if raw {
+		return string(data), nil
+	}
+	return trimNewline(string(data)), nil
+}
+
+// DirLoader returns a loader resolving each key by reading the file dir/KEY, as laid out by envdir and Docker secrets under /run/secrets,
+// trimming a single trailing newline. Files are read on every lookup, so rotated secrets are picked up; keys that are missing, unreadable or
+// not plain file names are unset.
+func DirLoader(dir string) (EnvLoader, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open env dir: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("env dir %s is not a directory", dir)
+	}
+
+	return func(key string) string {
+		if key == "" || key == "." || key == ".." || strings.ContainsAny(key, `/\`) {
+			return ""
+		}
+		data, err := os.ReadFile(filepath.Join(dir, key))
+		if err != nil {
+			return ""
+		}
+		return trimNewline(string(data))
+	}, nil
+}
+
+// trimNewline removes a single trailing newline, as left by editors and `echo`, keeping any other trailing whitespace.
+func trimNewline(s string) string {
+	if s, ok := strings.CutSuffix(s, "\n"); ok {
+		return strings.TrimSuffix(s, "\r")
+	}
+	return s
+}
@@ -0,0 +1,112 @@
+package env_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ndisidore/go-env"
+)
+
+func TestWithSecretFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	secret := filepath.Join(dir, "db_password")
+	if err := os.WriteFile(secret, []byte("hunter2 \n"), 0o600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	loader := func(key string) string {
+		return map[string]string{
+			"DB_PASSWORD_FILE": secret,
+			"API_KEY":          "direct",
+			"API_KEY_FILE":     secret,
+			"MISSING_FILE":     filepath.Join(dir, "nope"),
+		}[key]
+	}
+	cases := []struct {
+		searchEnv           string
+		expected            string
+		expectedErrContains string
+	}{
+		{searchEnv: "DB_PASSWORD", expected: "hunter2 "},
+		{searchEnv: "API_KEY", expected: "direct"},
+		{searchEnv: "MISSING", expectedErrContains: "failed to read MISSING_FILE"},
+		{searchEnv: "UNSET", expected: "default"},
+	}
+	for _, tt := range cases {
+		t.Run(tt.searchEnv, func(t *testing.T) {
+			t.Parallel()
+			ret, err := env.FromEnvOrDefault(context.Background(), tt.searchEnv, "default", env.WithEnvLoader(loader), env.WithSecretFiles())
+			switch {
+			case err != nil && tt.expectedErrContains != "":
+				if !strings.Contains(err.Error(), tt.expectedErrContains) {
+					t.Logf("unexpected error: %v", err)
+					t.Fail()
+				}
+			case err != nil:
+				t.Logf("unexpected error: %v", err)
+				t.Fail()
+			case tt.expectedErrContains != "":
+				t.Logf("expected error containing %q", tt.expectedErrContains)
+				t.Fail()
+			case ret != tt.expected:
+				t.Logf("return value (%q) does not match expected (%q)", ret, tt.expected)
+				t.Fail()
+			}
+		})
+	}
+
+	if ret, err := env.FromEnvOrDefault(context.Background(), "DB_PASSWORD", "default", env.WithEnvLoader(loader)); err != nil || ret != "default" {
+		t.Logf("files should only be read with WithSecretFiles, got (%q, %v)", ret, err)
+		t.Fail()
+	}
+}
+
+func TestDirLoader(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	files := map[string]string{"DB_PASSWORD": "hunter2\n", "API_KEY": "abc\r\n", "MULTILINE": "line1\nline2\n\n", "EMPTY": ""}
+	for name, contents := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0o600); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(filepath.Dir(dir), "OUTSIDE"), []byte("leaked"), 0o600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	loader, err := env.DirLoader(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cases := []struct {
+		key      string
+		expected string
+	}{
+		{key: "DB_PASSWORD", expected: "hunter2"},
+		{key: "API_KEY", expected: "abc"},
+		{key: "MULTILINE", expected: "line1\nline2\n"},
+		{key: "EMPTY", expected: ""},
+		{key: "UNSET", expected: ""},
+		{key: "../OUTSIDE", expected: ""},
+	}
+	for _, tt := range cases {
+		if val := loader(tt.key); val != tt.expected {
+			t.Logf("%s: return value (%q) does not match expected (%q)", tt.key, val, tt.expected)
+			t.Fail()
+		}
+	}
+
+	if _, err := env.DirLoader(filepath.Join(dir, "DB_PASSWORD")); err == nil {
+		t.Log("expected an error for a file")
+		t.Fail()
+	}
+	if loader, err := env.LoaderFromURI("dir://" + dir); err != nil || loader("DB_PASSWORD") != "hunter2" {
+		t.Logf("dir scheme returned err %v", err)
+		t.Fail()
+	}
+}
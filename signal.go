@@ -0,0 +1,34 @@
+//go:build !tinygo && (unix || windows)
+
+package env
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// parseSignalDest parses an os.Signal destination, reporting whether ptr is one. Signals are given by name, with or without the `SIG` prefix and in
+// either case, e.g. `SIGTERM` or `term`, or by number up to the platform's highest signal, e.g. `15`.
+func parseSignalDest(ptr any, envStr string) (bool, error) {
+	sig, ok := ptr.(*os.Signal)
+	if !ok {
+		return false, nil
+	}
+
+	if n, err := strconv.Atoi(envStr); err == nil {
+		if n <= 0 || n > maxSignal {
+			return true, fmt.Errorf("invalid signal number %d (want 1 to %d)", n, maxSignal)
+		}
+		*sig = syscall.Signal(n)
+		return true, nil
+	}
+	name := strings.ToUpper(envStr)
+	if s, ok := signalsByName[strings.TrimPrefix(name, "SIG")]; ok {
+		*sig = s
+		return true, nil
+	}
+	return true, fmt.Errorf("unknown signal %q (want e.g. SIGTERM, TERM or 15)", envStr)
+}
@@ -0,0 +1,6 @@
+//go:build !tinygo && unix && !linux
+
+package env
+
+// maxSignal is the highest signal number shared by the other Unix platforms, which either lack real-time signals or number them differently.
+const maxSignal = 31
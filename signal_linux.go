@@ -0,0 +1,6 @@
+//go:build !tinygo
+
+package env
+
+// maxSignal is the highest signal number on Linux, SIGRTMAX, as real-time signals can be given by number.
+const maxSignal = 64
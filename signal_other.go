@@ -0,0 +1,8 @@
+//go:build tinygo || !(unix || windows)
+
+package env
+
+// parseSignalDest reports that os.Signal destinations are not supported on this platform, leaving them to the generic fallbacks.
+func parseSignalDest(any, string) (bool, error) {
+	return false, nil
+}
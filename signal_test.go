@@ -0,0 +1,80 @@
+//go:build !tinygo && unix
+
+package env_test
+
+import (
+	"context"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/ndisidore/go-env"
+)
+
+func TestSignal(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name                string
+		value               string
+		expected            os.Signal
+		expectedErrContains string
+	}{
+		{name: "unset", value: "", expected: syscall.SIGTERM},
+		{name: "full name", value: "SIGHUP", expected: syscall.SIGHUP},
+		{name: "short name", value: "usr1", expected: syscall.SIGUSR1},
+		{name: "number", value: "2", expected: syscall.SIGINT},
+		{name: "unknown", value: "SIGNOPE", expectedErrContains: `unknown signal "SIGNOPE"`},
+		{name: "negative", value: "-9", expectedErrContains: "invalid signal number -9"},
+		{name: "out of range", value: "99999", expectedErrContains: "invalid signal number 99999"},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ret, err := env.FromEnvOrDefaultAny(context.Background(), "STOP_SIGNAL", os.Signal(syscall.SIGTERM), env.WithEnvLoader(func(string) string { return tt.value }))
+			switch {
+			case err != nil && tt.expectedErrContains == "":
+				t.Logf("unexpected error: %v", err)
+				t.Fail()
+			case err != nil:
+				if !strings.Contains(err.Error(), tt.expectedErrContains) {
+					t.Logf("error (%v) does not contain expected (%s)", err, tt.expectedErrContains)
+					t.Fail()
+				}
+			case tt.expectedErrContains != "":
+				t.Logf("expected error containing %q", tt.expectedErrContains)
+				t.Fail()
+			case ret != tt.expected:
+				t.Logf("return value (%v) does not match expected (%v)", ret, tt.expected)
+				t.Fail()
+			}
+		})
+	}
+}
+
+func TestUnmarshalSignal(t *testing.T) {
+	t.Parallel()
+
+	var cfg struct {
+		Stop   os.Signal `env:"STOP_SIGNAL"`
+		Reload os.Signal `env:"RELOAD_SIGNAL"`
+		Unset  os.Signal `env:"UNSET_SIGNAL"`
+	}
+	loader := func(key string) string {
+		return map[string]string{"STOP_SIGNAL": "SIGINT", "RELOAD_SIGNAL": "hup"}[key]
+	}
+	if err := env.Unmarshal(context.Background(), &cfg, env.WithEnvLoader(loader)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Stop != syscall.SIGINT || cfg.Reload != syscall.SIGHUP || cfg.Unset != nil {
+		t.Logf("unexpected signals: %+v", cfg)
+		t.Fail()
+	}
+
+	bad := func(string) string { return "99999" }
+	if err := env.Unmarshal(context.Background(), &cfg, env.WithEnvLoader(bad)); err == nil || !strings.Contains(err.Error(), "invalid signal number 99999") {
+		t.Logf("unexpected error: %v", err)
+		t.Fail()
+	}
+}
@@ -0,0 +1,16 @@
+//go:build !tinygo
+
+package env
+
+import "syscall"
+
+// signalsByName maps the names of the signals defined on Windows, without the `SIG` prefix, to their values. Only os.Interrupt and os.Kill can
+// actually be sent to a process.
+var signalsByName = map[string]syscall.Signal{
+	"HUP": syscall.SIGHUP, "INT": syscall.SIGINT, "QUIT": syscall.SIGQUIT, "ILL": syscall.SIGILL, "TRAP": syscall.SIGTRAP, "ABRT": syscall.SIGABRT,
+	"BUS": syscall.SIGBUS, "FPE": syscall.SIGFPE, "KILL": syscall.SIGKILL, "SEGV": syscall.SIGSEGV, "PIPE": syscall.SIGPIPE, "ALRM": syscall.SIGALRM,
+	"TERM": syscall.SIGTERM,
+}
+
+// maxSignal is the highest signal number defined on Windows, SIGTERM.
+const maxSignal = 15
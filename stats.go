@@ -0,0 +1,83 @@
+package env
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+	"time"
+)
+
+type (
+	// KeyStats summarizes the resolutions of a single env var.
+	KeyStats struct {
+		Key string
+		// Resolutions is the number of times the key was parsed.
+		Resolutions int
+		// Total is the time spent across all resolutions, including loader lookups, and Max the longest single resolution.
+		Total time.Duration
+		Max   time.Duration
+	}
+
+	// Stats summarizes the time a Parser has spent resolving env vars, e.g. to attribute startup regressions to slow remote config backends.
+	Stats struct {
+		// Total is the time spent across all keys.
+		Total time.Duration
+		// Keys holds per-key statistics, slowest first.
+		Keys []KeyStats
+	}
+
+	// statsRecorder accumulates resolution timings. It is shared by every copy of the options it was enabled on.
+	statsRecorder struct {
+		mu   sync.Mutex
+		keys map[string]*KeyStats
+	}
+)
+
+// WithStats records how long each env var takes to resolve, as reported by Parser.Stats. Timings are measured using the configured clock.
+//
+// Every parser derived from, or call made with, options including WithStats contributes to the same totals.
+func WithStats() EnvParseOption {
+	return func(o *envParseOpts) error {
+		o.stats = &statsRecorder{keys: make(map[string]*KeyStats)}
+		return nil
+	}
+}
+
+// Stats reports the resolution timings recorded since the parser was built. It is empty unless the parser was built with WithStats.
+func (p *Parser) Stats() Stats {
+	var s Stats
+	if p.opts.stats == nil {
+		return s
+	}
+
+	p.opts.stats.mu.Lock()
+	defer p.opts.stats.mu.Unlock()
+	s.Keys = make([]KeyStats, 0, len(p.opts.stats.keys))
+	for _, ks := range p.opts.stats.keys {
+		s.Total += ks.Total
+		s.Keys = append(s.Keys, *ks)
+	}
+	slices.SortFunc(s.Keys, func(a, b KeyStats) int {
+		if c := cmp.Compare(b.Total, a.Total); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Key, b.Key)
+	})
+	return s
+}
+
+// record adds a resolution of key that began at start.
+func (r *statsRecorder) record(clock Clock, key string, start time.Time) {
+	elapsed := clock.Now().Sub(start)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ks, ok := r.keys[key]
+	if !ok {
+		ks = &KeyStats{Key: key}
+		r.keys[key] = ks
+	}
+	ks.Resolutions++
+	ks.Total += elapsed
+	ks.Max = max(ks.Max, elapsed)
+}
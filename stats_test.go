@@ -0,0 +1,61 @@
+package env_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ndisidore/go-env"
+)
+
+func TestParserStats(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock(time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC))
+	latency := map[string]time.Duration{"DATABASE_URL": 300 * time.Millisecond, "PORT": time.Millisecond}
+	loader := func(key string) string {
+		clock.Advance(latency[key])
+		return map[string]string{"DATABASE_URL": "postgres://db", "PORT": "8080"}[key]
+	}
+	p, err := env.NewParser(env.WithEnvLoader(loader), env.WithClock(clock), env.WithStats())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, key := range []string{"PORT", "DATABASE_URL", "PORT"} {
+		if _, err := env.GetOrDefault(context.Background(), p, key, ""); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if _, err := env.GetOrDefault(context.Background(), p.WithOverlay(map[string]string{"UNSET": "x"}), "UNSET", ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	stats := p.Stats()
+	expected := []env.KeyStats{
+		{Key: "DATABASE_URL", Resolutions: 1, Total: 300 * time.Millisecond, Max: 300 * time.Millisecond},
+		{Key: "PORT", Resolutions: 2, Total: 2 * time.Millisecond, Max: time.Millisecond},
+		{Key: "UNSET", Resolutions: 1},
+	}
+	if stats.Total != 302*time.Millisecond || len(stats.Keys) != len(expected) {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+	for i, ks := range stats.Keys {
+		if ks != expected[i] {
+			t.Logf("key stats (%+v) do not match expected (%+v)", ks, expected[i])
+			t.Fail()
+		}
+	}
+
+	bare, err := env.NewParser(env.WithEnvLoader(loader))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := env.GetOrDefault(context.Background(), bare, "PORT", ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stats := bare.Stats(); stats.Total != 0 || len(stats.Keys) != 0 {
+		t.Logf("stats should be empty without WithStats: %+v", stats)
+		t.Fail()
+	}
+}
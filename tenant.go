@@ -0,0 +1,68 @@
+package env
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// tenantPrefix is the prefix of per-tenant override keys, which take the form `TENANT_<ID>_<KEY>`.
+const tenantPrefix = "TENANT_"
+
+// Tenant returns a derived parser whose lookups consult `TENANT_<ID>_<KEY>` first and fall back to `<KEY>`, layering a tenant's overrides over the global config.
+// The id is upper-cased and must not contain an underscore, so that tenant keys can be attributed unambiguously.
+func (p *Parser) Tenant(id string) (*Parser, error) {
+	if id == "" || strings.Contains(id, "_") {
+		return nil, fmt.Errorf("invalid tenant id %q: must be non-empty and contain no underscore", id)
+	}
+
+	prefix := tenantPrefix + strings.ToUpper(id) + "_"
+	derived := &Parser{opts: p.opts}
+	base := p.opts.envLoader
+	derived.opts.envLoader = func(key string) string {
+		if v := base(prefix + key); v != "" {
+			return v
+		}
+		return base(key)
+	}
+	if baseLookuper := p.opts.envLookuper; baseLookuper != nil {
+		derived.opts.envLookuper = func(key string) (string, bool) {
+			if v, ok := baseLookuper(prefix + key); ok && v != "" {
+				return v, true
+			}
+			return baseLookuper(key)
+		}
+	}
+	if baseLister := p.opts.keyLister; baseLister != nil {
+		derived.opts.keyLister = func() []string {
+			keys := baseLister()
+			for _, k := range keys {
+				if stripped, ok := strings.CutPrefix(k, prefix); ok && stripped != "" {
+					keys = append(keys, stripped)
+				}
+			}
+			return keys
+		}
+	}
+	derived.opts.keys = new(keyIndex)
+
+	return derived, nil
+}
+
+// Tenants lists the ids of tenants with at least one `TENANT_<ID>_<KEY>` override, sorted lexically. It relies on key discovery, see Keys.
+func (p *Parser) Tenants() ([]string, error) {
+	keys, err := p.Keys(tenantPrefix + "*_*")
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	for _, key := range keys {
+		id, rest, _ := strings.Cut(strings.TrimPrefix(key, tenantPrefix), "_")
+		if id != "" && rest != "" {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	return slices.Compact(ids), nil
+}
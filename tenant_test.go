@@ -0,0 +1,57 @@
+package env_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/ndisidore/go-env"
+)
+
+func TestParserTenant(t *testing.T) {
+	t.Parallel()
+
+	vals := map[string]string{"DB_HOST": "shared", "PORT": "8080", "TENANT_ACME_DB_HOST": "acme-db", "TENANT_GLOBEX_PORT": "9090", "TENANT_": "x"}
+	loader := func(key string) string {
+		return vals[key]
+	}
+	lister := func() []string {
+		keys := make([]string, 0, len(vals))
+		for k := range vals {
+			keys = append(keys, k)
+		}
+		return keys
+	}
+	p, err := env.NewParser(env.WithEnvLoader(loader), env.WithKeyLister(lister))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ids, err := p.Tenants()
+	if err != nil || !reflect.DeepEqual(ids, []string{"ACME", "GLOBEX"}) {
+		t.Logf("Tenants returned (%v, %v)", ids, err)
+		t.Fail()
+	}
+
+	acme, err := p.Tenant("acme")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ret, err := acme.GetString(context.Background(), "DB_HOST", ""); err != nil || ret != "acme-db" {
+		t.Logf("GetString returned (%q, %v), expected the tenant override", ret, err)
+		t.Fail()
+	}
+	if ret, err := acme.GetInt(context.Background(), "PORT", 0); err != nil || ret != 8080 {
+		t.Logf("GetInt returned (%d, %v), expected the global value", ret, err)
+		t.Fail()
+	}
+	if keys, err := acme.Keys("DB_*"); err != nil || !reflect.DeepEqual(keys, []string{"DB_HOST"}) {
+		t.Logf("Keys returned (%v, %v)", keys, err)
+		t.Fail()
+	}
+
+	if _, err := p.Tenant("a_b"); err == nil {
+		t.Log("expected an error for a tenant id containing an underscore")
+		t.Fail()
+	}
+}
@@ -0,0 +1,96 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TimeWindow is a destination for a daily time range, e.g. maintenance windows or quiet hours, parsed from `22:00-06:00`. Times may include seconds,
+// and the end may be `24:00` to run until midnight. A window whose end is before its start wraps past midnight.
+//
+// By default the window is evaluated in the local time of the instant being checked. A trailing IANA zone name, e.g. `22:00-06:00 Europe/Berlin`,
+// evaluates it in that zone instead.
+type TimeWindow struct {
+	Window
+	// Location is the zone the window is evaluated in, nil for the zone of each instant checked.
+	Location *time.Location
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (w *TimeWindow) UnmarshalText(text []byte) error {
+	span, zone, hasZone := strings.Cut(strings.TrimSpace(string(text)), " ")
+	startStr, endStr, ok := strings.Cut(span, "-")
+	if !ok {
+		return fmt.Errorf("invalid time window %q (want e.g. 22:00-06:00)", text)
+	}
+	start, err := parseTimeOfDay(startStr, false)
+	if err != nil {
+		return err
+	}
+	end, err := parseTimeOfDay(endStr, true)
+	if err != nil {
+		return err
+	}
+	if start == end {
+		return fmt.Errorf("time window %q is empty", text)
+	}
+
+	var loc *time.Location
+	if hasZone {
+		if loc, err = time.LoadLocation(strings.TrimSpace(zone)); err != nil {
+			return fmt.Errorf("invalid time zone: %w", err)
+		}
+	}
+	*w = TimeWindow{Window: Window{Start: start, End: end}, Location: loc}
+	return nil
+}
+
+// In returns a copy of the window evaluated in loc, e.g. to give windows without a zone of their own a default one.
+func (w TimeWindow) In(loc *time.Location) TimeWindow {
+	w.Location = loc
+	return w
+}
+
+// Contains reports whether the time of day of t, in the window's zone if it has one, falls within the window. Start is inclusive and End exclusive.
+func (w TimeWindow) Contains(t time.Time) bool {
+	if w.Location != nil {
+		t = t.In(w.Location)
+	}
+	return w.Window.Contains(t)
+}
+
+// String returns the window in the form it is parsed from.
+func (w TimeWindow) String() string {
+	s := formatTimeOfDay(w.Start) + "-" + formatTimeOfDay(w.End)
+	if w.Location != nil {
+		s += " " + w.Location.String()
+	}
+	return s
+}
+
+// parseTimeOfDay parses `15:04` or `15:04:05` as an offset from midnight. `24:00` is accepted as the end of the day when allowMidnight is set.
+func parseTimeOfDay(in string, allowMidnight bool) (time.Duration, error) {
+	if allowMidnight && in == "24:00" {
+		return 24 * time.Hour, nil
+	}
+	layout := "15:04"
+	if strings.Count(in, ":") == 2 {
+		layout = "15:04:05"
+	}
+	t, err := time.Parse(layout, in)
+	if err != nil {
+		return 0, errors.New("invalid time of day " + in + " (want e.g. 22:00)")
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second, nil
+}
+
+// formatTimeOfDay formats an offset from midnight as `15:04`, adding seconds only when there are any.
+func formatTimeOfDay(d time.Duration) string {
+	h, m, s := int(d/time.Hour), int(d%time.Hour/time.Minute), int(d%time.Minute/time.Second)
+	if s != 0 {
+		return fmt.Sprintf("%02d:%02d:%02d", h, m, s)
+	}
+	return fmt.Sprintf("%02d:%02d", h, m)
+}
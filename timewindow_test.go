@@ -0,0 +1,83 @@
+package env_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ndisidore/go-env"
+)
+
+func TestTimeWindow(t *testing.T) {
+	t.Parallel()
+
+	loader := func(key string) string {
+		return map[string]string{
+			"QUIET_HOURS":    "22:00-06:00",
+			"MAINTENANCE":    "01:30-02:15:30 America/New_York",
+			"ALL_DAY":        "00:00-24:00",
+			"EMPTY":          "09:00-09:00",
+			"NO_RANGE":       "22:00",
+			"BAD_TIME":       "25:00-06:00",
+			"BAD_ZONE":       "22:00-06:00 Mars/Olympus",
+			"MIDNIGHT_START": "24:00-06:00",
+		}[key]
+	}
+	day := time.Date(2026, time.October, 15, 0, 0, 0, 0, time.UTC)
+
+	quiet, err := env.FromEnvOrDefaultAny(context.Background(), "QUIET_HOURS", env.TimeWindow{}, env.WithEnvLoader(loader))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for at, expected := range map[time.Duration]bool{23 * time.Hour: true, 2 * time.Hour: true, 6 * time.Hour: false, 12 * time.Hour: false, 22 * time.Hour: true} {
+		if got := quiet.Contains(day.Add(at)); got != expected {
+			t.Logf("Contains at %s (%t) does not match expected (%t)", at, got, expected)
+			t.Fail()
+		}
+	}
+	if tokyo, _ := time.LoadLocation("Asia/Tokyo"); !quiet.In(tokyo).Contains(day.Add(14 * time.Hour)) {
+		t.Log("expected 14:00 UTC to fall within quiet hours in Tokyo")
+		t.Fail()
+	}
+
+	maint, err := env.FromEnvOrDefaultAny(context.Background(), "MAINTENANCE", env.TimeWindow{}, env.WithEnvLoader(loader))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if maint.String() != "01:30-02:15:30 America/New_York" {
+		t.Logf("unexpected string form: %s", maint)
+		t.Fail()
+	}
+	// 06:00 UTC is 02:00 in New York during daylight saving time
+	if !maint.Contains(day.Add(6*time.Hour)) || maint.Contains(day.Add(90*time.Minute)) {
+		t.Log("expected the window to be evaluated in New York time")
+		t.Fail()
+	}
+	// on the day clocks fall back, 14:00 UTC is 09:00 in New York, 10 wall clock hours after midnight despite 11 hours elapsing
+	business := env.TimeWindow{Window: env.Window{Start: 9 * time.Hour, End: 17 * time.Hour}, Location: maint.Location}
+	fallBack := time.Date(2026, time.November, 1, 0, 0, 0, 0, time.UTC)
+	if !business.Contains(fallBack.Add(14*time.Hour)) || business.Contains(fallBack.Add(13*time.Hour+59*time.Minute)) {
+		t.Log("expected the window to follow the wall clock across the DST transition")
+		t.Fail()
+	}
+
+	allDay, err := env.FromEnvOrDefaultAny(context.Background(), "ALL_DAY", env.TimeWindow{}, env.WithEnvLoader(loader))
+	if err != nil || !allDay.Contains(day.Add(23*time.Hour+59*time.Minute)) {
+		t.Logf("FromEnvOrDefault returned (%v, %v)", allDay, err)
+		t.Fail()
+	}
+
+	for key, expected := range map[string]string{
+		"EMPTY":          "is empty",
+		"NO_RANGE":       "want e.g. 22:00-06:00",
+		"BAD_TIME":       "invalid time of day 25:00",
+		"BAD_ZONE":       "invalid time zone",
+		"MIDNIGHT_START": "invalid time of day 24:00",
+	} {
+		if _, err := env.FromEnvOrDefaultAny(context.Background(), key, env.TimeWindow{}, env.WithEnvLoader(loader)); err == nil || !strings.Contains(err.Error(), expected) {
+			t.Logf("unexpected error for %s: %v", key, err)
+			t.Fail()
+		}
+	}
+}
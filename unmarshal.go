@@ -0,0 +1,512 @@
+//go:build !tinygo
+
+package env
+
+import (
+	"context"
+	"encoding"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"net"
+	"net/mail"
+	"net/netip"
+	"net/url"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ErrImmutableField is returned by Parser.Reparse when a field tagged `immutable` would change.
+var ErrImmutableField = errors.New("immutable field cannot change on reparse")
+
+// ErrUnknownKey is returned by Unmarshal and Reparse under Strict for a key within the parser's namespace, or the reparse prefix, that no field
+// is tagged with, e.g. a misspelt override that would otherwise be silently ignored.
+var ErrUnknownKey = errors.New("unknown env key")
+
+// Unmarshal populates the struct pointed to by dest from env vars named by `env:"KEY"` struct tags.
+//
+// See Parser.Unmarshal for the details of how fields are resolved.
+func Unmarshal(ctx context.Context, dest any, opts ...EnvParseOption) error {
+	p, err := NewBuilder().With(opts...).Build()
+	if err != nil {
+		return err
+	}
+
+	return p.Unmarshal(ctx, dest)
+}
+
+// Unmarshal populates the struct pointed to by dest from env vars named by `env:"KEY"` struct tags.
+// Each field's current value acts as its default, so unset env vars leave the field untouched.
+//
+// Tagged fields may be of any Parseable type or implement one of the interfaces supported for custom types, or be a pointer to one, which is left nil when its env var is unset. Untagged struct fields are descended into and fields tagged `env:"-"` are skipped.
+// The key may be followed by modifiers: `required` behaves as WithRequired for that field, and a trailing `default=VALUE` is parsed like
+// an env value and takes precedence over the field's current value, e.g. `env:"PORT,required"` or `env:"HOSTS,default=a,b"`.
+// `immutable` marks a field that Reparse must refuse to change.
+// All fields are attempted; failures are returned together as Errors. Any options provided apply to this call only.
+func (p *Parser) Unmarshal(ctx context.Context, dest any, opts ...EnvParseOption) error {
+	parseOpts := p.opts
+	for _, opt := range opts {
+		if err := opt(&parseOpts); err != nil {
+			return fmt.Errorf("option error: %w", err)
+		}
+	}
+
+	rv := reflect.ValueOf(dest)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("unmarshal destination must be a non-nil pointer to a struct, got %T", dest)
+	}
+
+	fe := fieldErrors{limit: parseOpts.maxErrors}
+	unmarshalStruct(ctx, &parseOpts, "", rv.Elem(), &fe)
+	parseOpts.appendUnknownKeys(rv.Elem().Type(), &fe)
+	return fe.err()
+}
+
+// Reparse re-populates only the tagged fields of the struct pointed to by dest whose env var starts with prefix, e.g. to scope a hot reload to a
+// subsystem such as `DB_`. Other fields are left untouched.
+//
+// Fields are resolved as by Unmarshal, except that the update is all-or-nothing: if any field fails, or a field tagged `immutable` would change
+// (reported as ErrImmutableField), dest is not modified.
+func (p *Parser) Reparse(ctx context.Context, dest any, prefix string, opts ...EnvParseOption) error {
+	parseOpts := p.opts
+	for _, opt := range opts {
+		if err := opt(&parseOpts); err != nil {
+			return fmt.Errorf("option error: %w", err)
+		}
+	}
+	if prefix == "" {
+		return errors.New("reparse prefix cannot be empty string")
+	}
+	parseOpts.keyPrefix = prefix
+
+	rv := reflect.ValueOf(dest)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("reparse destination must be a non-nil pointer to a struct, got %T", dest)
+	}
+
+	staged := reflect.New(rv.Elem().Type()).Elem()
+	staged.Set(rv.Elem())
+	fe := fieldErrors{limit: parseOpts.maxErrors}
+	unmarshalStruct(ctx, &parseOpts, "", staged, &fe)
+	parseOpts.appendUnknownKeys(staged.Type(), &fe)
+	if err := fe.err(); err != nil {
+		return err
+	}
+	rv.Elem().Set(staged)
+	return nil
+}
+
+// WithMaxErrors stops resolving a struct's fields once n of them have failed, keeping the errors returned by Unmarshal and Reparse readable.
+// The fields left unevaluated are counted by a *TruncatedError appended to the returned Errors.
+func WithMaxErrors(n int) EnvParseOption {
+	return func(o *envParseOpts) error {
+		if n <= 0 {
+			return errors.New("max errors must be positive")
+		}
+
+		o.maxErrors = n
+		return nil
+	}
+}
+
+// fieldErrors collects the failures of a struct's fields, skipping further fields once the limit set by WithMaxErrors is reached.
+type fieldErrors struct {
+	errs    Errors
+	limit   int
+	skipped int
+}
+
+// full reports whether the limit has been reached, so that further fields should be skipped.
+func (fe *fieldErrors) full() bool {
+	return fe.limit > 0 && len(fe.errs) >= fe.limit
+}
+
+// err returns the collected failures, if any, followed by a *TruncatedError if fields were skipped.
+func (fe *fieldErrors) err() error {
+	if fe.skipped == 0 {
+		return fe.errs.ErrOrNil()
+	}
+	return append(slices.Clip(fe.errs), &TruncatedError{Limit: fe.limit, Skipped: fe.skipped})
+}
+
+// unmarshalStruct resolves each exported field of sv, appending failures to fe. Field names are reported relative to the outermost struct, prefixed by path.
+func unmarshalStruct(ctx context.Context, parseOpts *envParseOpts, path string, sv reflect.Value, fe *fieldErrors) {
+	st := sv.Type()
+	for i := 0; i < st.NumField(); i++ {
+		sf := st.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		fv := sv.Field(i)
+		tag, tagged := sf.Tag.Lookup("env")
+		switch {
+		case tag == "-":
+		case !tagged && sf.Type.Kind() == reflect.Struct && !isCustomDest(fv.Addr().Interface()):
+			unmarshalStruct(ctx, parseOpts, path+sf.Name+".", fv, fe)
+		case !tagged:
+		case fe.full():
+			fe.skipped++
+		default:
+			ft, err := parseFieldTag(tag)
+			if err != nil {
+				fe.errs.Append(fmt.Errorf("field %s: %w", path+sf.Name, err))
+				continue
+			}
+			if !strings.HasPrefix(ft.key, parseOpts.keyPrefix) {
+				continue
+			}
+			// a reparse must not change immutable fields, as the application cannot apply them at runtime
+			var prev reflect.Value
+			if ft.immutable && parseOpts.keyPrefix != "" {
+				prev = reflect.New(fv.Type()).Elem()
+				prev.Set(fv)
+			}
+			fe.errs.Append(unmarshalTaggedField(ctx, *parseOpts, path+sf.Name, ft, fv.Addr().Interface()))
+			if prev.IsValid() && !reflect.DeepEqual(prev.Interface(), fv.Interface()) {
+				fe.errs.Append(fmt.Errorf("field %s (%s): %w", path+sf.Name, ft.key, ErrImmutableField))
+				fv.Set(prev)
+			}
+		}
+	}
+}
+
+// appendUnknownKeys appends an ErrUnknownKey to fe for each key within the namespace, and reparse prefix, that no tagged field of st consumes.
+// Detection is enabled by Strict and requires a namespace or prefix, as the rest of the environment belongs to other programs, and key discovery.
+func (o *envParseOpts) appendUnknownKeys(st reflect.Type, fe *fieldErrors) {
+	scope := o.namespace + o.keyPrefix
+	if !o.unknownKeys || scope == "" || o.keyLister == nil {
+		return
+	}
+
+	consumed := make(map[string]bool)
+	taggedKeys(st, func(key string) {
+		consumed[o.namespace+key] = true
+		if o.secretFiles {
+			consumed[o.namespace+key+fileSuffix] = true
+		}
+	})
+	for _, d := range o.deprecatedKeys {
+		consumed[d.key] = true
+	}
+	for _, key := range keysWithPrefix(o.sortedKeys(), scope) {
+		indexed := o.indexedPrefix != "" && strings.HasPrefix(key, o.indexedPrefix)
+		if !consumed[key] && !indexed && !o.machineManaged(key) {
+			fe.errs.Append(fmt.Errorf("%w %s: no field is tagged with it", ErrUnknownKey, key))
+		}
+	}
+}
+
+// taggedKeys calls fn with the key of each tagged field of st, descending into untagged struct fields as unmarshalStruct does.
+func taggedKeys(st reflect.Type, fn func(key string)) {
+	for i := 0; i < st.NumField(); i++ {
+		sf := st.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		tag, tagged := sf.Tag.Lookup("env")
+		switch {
+		case tag == "-":
+		case !tagged && sf.Type.Kind() == reflect.Struct && !isCustomDest(reflect.New(sf.Type).Interface()):
+			taggedKeys(sf.Type, fn)
+		case !tagged:
+		default:
+			if ft, err := parseFieldTag(tag); err == nil {
+				fn(ft.key)
+			}
+		}
+	}
+}
+
+// fieldTag is the parsed form of an `env:"KEY,modifier,..."` struct tag.
+type fieldTag struct {
+	key        string
+	required   bool
+	immutable  bool
+	def        string
+	hasDefault bool
+}
+
+// parseFieldTag parses an env tag. A `default=` modifier must come last, as its value runs to the end of the tag and may itself contain commas.
+func parseFieldTag(tag string) (fieldTag, error) {
+	key, modifiers, _ := strings.Cut(tag, ",")
+	ft := fieldTag{key: key}
+	if key == "" {
+		return ft, errors.New("empty env tag key")
+	}
+	for modifiers != "" {
+		if def, ok := strings.CutPrefix(modifiers, "default="); ok {
+			ft.def, ft.hasDefault = def, true
+			break
+		}
+
+		var mod string
+		mod, modifiers, _ = strings.Cut(modifiers, ",")
+		switch mod {
+		case "required":
+			ft.required = true
+		case "immutable":
+			ft.immutable = true
+		default:
+			return ft, fmt.Errorf("unknown env tag modifier %q", mod)
+		}
+	}
+	return ft, nil
+}
+
+// unmarshalTaggedField applies the tag's modifiers to a copy of the options and parses the field.
+// A tag default is parsed into the field first, using the same parser as the env value, so that it becomes the field's default.
+func unmarshalTaggedField(ctx context.Context, parseOpts envParseOpts, field string, ft fieldTag, ptr any) error {
+	if ft.hasDefault && ft.def != "" {
+		defOpts := parseOpts
+		defOpts.envLoader = func(string) string { return ft.def }
+		// a bad default is a programming error, so it is never swallowed by WithFallbackToDefaultOnError
+		defOpts.deprecatedKeys, defOpts.indexedPrefix, defOpts.required, defOpts.defaultOnError, defOpts.warningHandler, defOpts.stats = nil, "", false, false, nil, nil
+		if err := unmarshalField(ctx, &defOpts, ft.key, ptr); err != nil {
+			return fmt.Errorf("invalid default for %s: %w", ft.key, err)
+		}
+	}
+
+	parseOpts.required = parseOpts.required || ft.required
+	if parseOpts.provenance == nil {
+		return unmarshalField(ctx, &parseOpts, ft.key, ptr)
+	}
+
+	record := parseOpts.trackProvenance(ctx, ft.key)
+	err := unmarshalField(ctx, &parseOpts, ft.key, ptr)
+	parseOpts.provenance[field] = record()
+	return err
+}
+
+// unmarshalField parses key into the field ptr points to, keeping its current value as the default.
+func unmarshalField(ctx context.Context, parseOpts *envParseOpts, key string, ptr any) error {
+	switch fp := ptr.(type) {
+	case *string:
+		return setField(ctx, parseOpts, key, fp)
+	case *bool:
+		return setField(ctx, parseOpts, key, fp)
+	case *int:
+		return setField(ctx, parseOpts, key, fp)
+	case *uint:
+		return setField(ctx, parseOpts, key, fp)
+	case *int64:
+		return setField(ctx, parseOpts, key, fp)
+	case *uint64:
+		return setField(ctx, parseOpts, key, fp)
+	case *int8:
+		return setField(ctx, parseOpts, key, fp)
+	case *int16:
+		return setField(ctx, parseOpts, key, fp)
+	case *int32:
+		return setField(ctx, parseOpts, key, fp)
+	case *uint8:
+		return setField(ctx, parseOpts, key, fp)
+	case *uint16:
+		return setField(ctx, parseOpts, key, fp)
+	case *uint32:
+		return setField(ctx, parseOpts, key, fp)
+	case *float32:
+		return setField(ctx, parseOpts, key, fp)
+	case *float64:
+		return setField(ctx, parseOpts, key, fp)
+	case *time.Duration:
+		return setField(ctx, parseOpts, key, fp)
+	case *time.Time:
+		return setField(ctx, parseOpts, key, fp)
+	case *url.URL:
+		return setField(ctx, parseOpts, key, fp)
+	case *[]string:
+		return setField(ctx, parseOpts, key, fp)
+	case *[]bool:
+		return setField(ctx, parseOpts, key, fp)
+	case *[]int:
+		return setField(ctx, parseOpts, key, fp)
+	case *[]uint:
+		return setField(ctx, parseOpts, key, fp)
+	case *[]int64:
+		return setField(ctx, parseOpts, key, fp)
+	case *[]uint64:
+		return setField(ctx, parseOpts, key, fp)
+	case *[]int8:
+		return setField(ctx, parseOpts, key, fp)
+	case *[]int16:
+		return setField(ctx, parseOpts, key, fp)
+	case *[]int32:
+		return setField(ctx, parseOpts, key, fp)
+	case *[]uint16:
+		return setField(ctx, parseOpts, key, fp)
+	case *[]uint32:
+		return setField(ctx, parseOpts, key, fp)
+	case *[]float32:
+		return setField(ctx, parseOpts, key, fp)
+	case *[]float64:
+		return setField(ctx, parseOpts, key, fp)
+	case *[]time.Duration:
+		return setField(ctx, parseOpts, key, fp)
+	case *[]time.Time:
+		return setField(ctx, parseOpts, key, fp)
+	case *[]url.URL:
+		return setField(ctx, parseOpts, key, fp)
+	case *[]byte:
+		return setField(ctx, parseOpts, key, fp)
+	case *net.IP:
+		return setField(ctx, parseOpts, key, fp)
+	case *netip.Addr:
+		return setField(ctx, parseOpts, key, fp)
+	case *netip.Prefix:
+		return setField(ctx, parseOpts, key, fp)
+	case **net.IPNet:
+		return setField(ctx, parseOpts, key, fp)
+	case *[]net.IP:
+		return setField(ctx, parseOpts, key, fp)
+	case *[]netip.Addr:
+		return setField(ctx, parseOpts, key, fp)
+	case *[]netip.Prefix:
+		return setField(ctx, parseOpts, key, fp)
+	case *[]*net.IPNet:
+		return setField(ctx, parseOpts, key, fp)
+	case *mail.Address:
+		return setField(ctx, parseOpts, key, fp)
+	case *[]mail.Address:
+		return setField(ctx, parseOpts, key, fp)
+	case *slog.Level:
+		return setField(ctx, parseOpts, key, fp)
+	case *Date:
+		return setField(ctx, parseOpts, key, fp)
+	case *[]Date:
+		return setField(ctx, parseOpts, key, fp)
+	case *TimeOfDay:
+		return setField(ctx, parseOpts, key, fp)
+	case *[]TimeOfDay:
+		return setField(ctx, parseOpts, key, fp)
+	case *CountryCode:
+		return setField(ctx, parseOpts, key, fp)
+	case *[]CountryCode:
+		return setField(ctx, parseOpts, key, fp)
+	case *fs.FileMode:
+		return setField(ctx, parseOpts, key, fp)
+	case *map[string]string:
+		return setField(ctx, parseOpts, key, fp)
+	case *map[string]bool:
+		return setField(ctx, parseOpts, key, fp)
+	case *map[string]int:
+		return setField(ctx, parseOpts, key, fp)
+	case *map[string]uint:
+		return setField(ctx, parseOpts, key, fp)
+	case *map[string]int64:
+		return setField(ctx, parseOpts, key, fp)
+	case *map[string]uint64:
+		return setField(ctx, parseOpts, key, fp)
+	case *map[string]float64:
+		return setField(ctx, parseOpts, key, fp)
+	case *map[string]time.Duration:
+		return setField(ctx, parseOpts, key, fp)
+	}
+
+	pt := reflect.TypeOf(ptr)
+	typ := pt.Elem().String()
+	if pt.Elem().Kind() == reflect.Pointer {
+		return unmarshalPointerField(ctx, parseOpts, key, reflect.ValueOf(ptr).Elem())
+	}
+	if sd, ok := ptr.(scheduledDest); ok {
+		envStr, err := parse(ctx, parseOpts, key, "")
+		if err != nil || envStr == "" {
+			return err
+		}
+		if err := sd.parseSchedule(ctx, parseOpts, key, envStr); err != nil {
+			return parseOpts.parseError(key, typ, err)
+		}
+		return nil
+	}
+	if _, ok := ptr.(*os.Signal); ok {
+		envStr, err := parse(ctx, parseOpts, key, "")
+		if err != nil || envStr == "" {
+			return err
+		}
+		supported, err := parseSignalDest(ptr, envStr)
+		if !supported {
+			err = errors.New("unsupported destination type " + typ)
+		}
+		if err != nil {
+			return parseOpts.parseError(key, typ, err)
+		}
+		return nil
+	}
+	// a typed nil pointer identifies the type in the implementation registry, matching parseFallback
+	implKey := reflect.Zero(pt).Interface()
+	registered := hasImplementations(implKey)
+	if !registered && !isCustomDest(ptr) {
+		return parseOpts.parseError(key, typ, errors.New("unsupported destination type "+typ))
+	}
+
+	envStr, err := parse(ctx, parseOpts, key, "")
+	if err != nil || envStr == "" {
+		return err
+	}
+	decode := func() error {
+		if !registered {
+			// decode into a copy so a decoder abandoned by WithDecodeTimeout never writes to the struct
+			tmp := reflect.New(pt.Elem())
+			tmp.Elem().Set(reflect.ValueOf(ptr).Elem())
+			return parseOpts.guardedDecode(ctx, tmp.Interface(), envStr, func() {
+				reflect.ValueOf(ptr).Elem().Set(tmp.Elem())
+			})
+		}
+		impl, _, err := lookupImplementation(implKey, envStr)
+		if err == nil {
+			reflect.ValueOf(ptr).Elem().Set(reflect.ValueOf(impl))
+		}
+		return err
+	}
+	if err := decode(); err != nil {
+		if parseOpts.defaultOnError {
+			parseOpts.warn(Warning{Kind: WarnDefaultOnError, EnvVar: key, Key: key, Err: err})
+			return nil
+		}
+		return parseOpts.parseError(key, typ, err)
+	}
+	return nil
+}
+
+// unmarshalPointerField parses key into a new value for the pointer field fv, leaving fv untouched when the value is unset, so an unset env var
+// keeps a nil field nil. A non-nil field's current value is the default, as for any other field.
+func unmarshalPointerField(ctx context.Context, parseOpts *envParseOpts, key string, fv reflect.Value) error {
+	elem := reflect.New(fv.Type().Elem())
+	if !fv.IsNil() {
+		elem.Elem().Set(fv.Elem())
+	}
+
+	o := *parseOpts
+	o.required, o.defaultOnError = true, false
+	if absent, err := parseOpts.absent(parseOpts.namespace+key, unmarshalField(ctx, &o, key, elem.Interface())); absent || err != nil {
+		return err
+	}
+	fv.Set(elem)
+	return nil
+}
+
+// setField parses key into *ptr, using the current value as the default.
+func setField[T any](ctx context.Context, parseOpts *envParseOpts, key string, ptr *T) error {
+	v, err := parse(ctx, parseOpts, key, *ptr)
+	if err != nil {
+		return err
+	}
+	*ptr = v
+	return nil
+}
+
+// isCustomDest reports whether ptr implements one of the interfaces used to parse custom types.
+func isCustomDest(ptr any) bool {
+	switch ptr.(type) {
+	case encoding.TextUnmarshaler, encoding.BinaryUnmarshaler, flag.Value:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,359 @@
+//go:build !tinygo
+
+package env_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ndisidore/go-env"
+)
+
+func TestUnmarshal(t *testing.T) {
+	t.Parallel()
+
+	type (
+		database struct {
+			Hosts   []string      `env:"DB_HOSTS"`
+			Timeout time.Duration `env:"DB_TIMEOUT"`
+		}
+		config struct {
+			Name     string     `env:"NAME"`
+			Port     int        `env:"PORT"`
+			Level    slog.Level `env:"LOG_LEVEL"`
+			Debug    bool       `env:"DEBUG"`
+			Retries  int        `env:"RETRIES,default=3"`
+			Zones    []string   `env:"ZONES,default=a,b"`
+			Region   string     `env:"NAME,default=us-east-1"`
+			Ignored  string     `env:"-"`
+			Database database
+			internal string
+		}
+	)
+	loader := func(key string) string {
+		return map[string]string{"NAME": "svc", "LOG_LEVEL": "warn", "DB_HOSTS": "a,b", "DB_TIMEOUT": "5s", "-": "nope"}[key]
+	}
+
+	cfg := config{Port: 8080, Ignored: "kept"}
+	if err := env.Unmarshal(context.Background(), &cfg, env.WithEnvLoader(loader)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Name != "svc" || cfg.Port != 8080 || cfg.Level != slog.LevelWarn || cfg.Debug || cfg.Ignored != "kept" {
+		t.Logf("unexpected config: %+v", cfg)
+		t.Fail()
+	}
+	if cfg.Retries != 3 || len(cfg.Zones) != 2 || cfg.Region != "svc" {
+		t.Logf("tag defaults were not applied: %+v", cfg)
+		t.Fail()
+	}
+	if len(cfg.Database.Hosts) != 2 || cfg.Database.Timeout != 5*time.Second {
+		t.Logf("nested struct was not populated: %+v", cfg.Database)
+		t.Fail()
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	t.Parallel()
+
+	type config struct {
+		Port    int           `env:"PORT"`
+		Timeout time.Duration `env:"TIMEOUT"`
+		Chan    chan int      `env:"CHAN"`
+		Host    string        `env:"HOST,required"`
+		Name    string        `env:"NAME,optional"`
+		Retries int           `env:"RETRIES,default=three"`
+	}
+	loader := func(key string) string {
+		return map[string]string{"PORT": "abc", "TIMEOUT": "30", "CHAN": "x"}[key]
+	}
+
+	var cfg config
+	err := env.Unmarshal(context.Background(), &cfg, env.WithEnvLoader(loader))
+	var errs env.Errors
+	if !errors.As(err, &errs) || len(errs) != 6 {
+		t.Fatalf("expected all six fields to fail, got %v", err)
+	}
+	var pe *env.ParseError
+	if !errors.As(errs[0], &pe) || pe.EnvVar != "PORT" {
+		t.Logf("unexpected first error: %v", errs[0])
+		t.Fail()
+	}
+	if !strings.Contains(errs[2].Error(), "unsupported destination type chan int") {
+		t.Logf("unexpected unsupported type error: %v", errs[2])
+		t.Fail()
+	}
+	var me *env.MissingError
+	if !errors.As(errs[3], &me) || me.EnvVar != "HOST" {
+		t.Logf("unexpected required error: %v", errs[3])
+		t.Fail()
+	}
+	if !strings.Contains(errs[4].Error(), `unknown env tag modifier "optional"`) {
+		t.Logf("unexpected modifier error: %v", errs[4])
+		t.Fail()
+	}
+	if !errors.As(errs[5], &pe) || !strings.Contains(errs[5].Error(), "invalid default for RETRIES") {
+		t.Logf("unexpected default error: %v", errs[5])
+		t.Fail()
+	}
+
+	if err := env.Unmarshal(context.Background(), cfg, env.WithEnvLoader(loader)); err == nil {
+		t.Log("expected an error for a non-pointer destination")
+		t.Fail()
+	}
+}
+
+func TestUnmarshalWithMaxErrors(t *testing.T) {
+	t.Parallel()
+
+	type (
+		limits struct {
+			Burst int `env:"BURST"`
+			Rate  int `env:"RATE"`
+		}
+		config struct {
+			Port    int `env:"PORT"`
+			Workers int `env:"WORKERS"`
+			Limits  limits
+			Name    string `env:"NAME"`
+		}
+	)
+	loader := func(key string) string {
+		return map[string]string{"PORT": "a", "WORKERS": "b", "BURST": "c", "RATE": "d", "NAME": "svc"}[key]
+	}
+
+	var cfg config
+	err := env.Unmarshal(context.Background(), &cfg, env.WithEnvLoader(loader), env.WithMaxErrors(2))
+	var errs env.Errors
+	if !errors.As(err, &errs) || len(errs) != 3 {
+		t.Fatalf("expected two failures and a truncation, got %v", err)
+	}
+	var te *env.TruncatedError
+	if !errors.As(errs[2], &te) || te.Limit != 2 || te.Skipped != 3 {
+		t.Logf("unexpected truncation: %v", errs[2])
+		t.Fail()
+	}
+	if cfg.Name != "" {
+		t.Logf("fields after the limit should not be evaluated: %+v", cfg)
+		t.Fail()
+	}
+
+	if err := env.Unmarshal(context.Background(), &cfg, env.WithEnvLoader(loader), env.WithMaxErrors(10)); errors.As(err, &te) {
+		t.Logf("unexpected truncation below the limit: %v", err)
+		t.Fail()
+	}
+	if err := env.Unmarshal(context.Background(), &cfg, env.WithMaxErrors(0)); err == nil {
+		t.Log("expected an error for a non-positive limit")
+		t.Fail()
+	}
+}
+
+func TestUnmarshalPointers(t *testing.T) {
+	t.Parallel()
+
+	type config struct {
+		Retries *int           `env:"RETRIES"`
+		Debug   *bool          `env:"DEBUG"`
+		Timeout *time.Duration `env:"TIMEOUT"`
+		Workers *int           `env:"WORKERS,default=4"`
+		Kept    *string        `env:"KEPT"`
+		Port    *int           `env:"PORT"`
+	}
+	loader := func(key string) string {
+		return map[string]string{"RETRIES": "0", "TIMEOUT": "1s", "PORT": "abc"}[key]
+	}
+
+	kept := "kept"
+	cfg := config{Kept: &kept}
+	err := env.Unmarshal(context.Background(), &cfg, env.WithEnvLoader(loader))
+	var pe *env.ParseError
+	if !errors.As(err, &pe) || pe.EnvVar != "PORT" {
+		t.Logf("expected a parse error for PORT, got %v", err)
+		t.Fail()
+	}
+	if cfg.Retries == nil || *cfg.Retries != 0 || cfg.Timeout == nil || *cfg.Timeout != time.Second {
+		t.Logf("set fields should be non-nil: %+v", cfg)
+		t.Fail()
+	}
+	if cfg.Debug != nil || cfg.Port != nil {
+		t.Logf("unset and failed fields should stay nil: %+v", cfg)
+		t.Fail()
+	}
+	if cfg.Workers == nil || *cfg.Workers != 4 || cfg.Kept != &kept {
+		t.Logf("defaults were not applied: %+v", cfg)
+		t.Fail()
+	}
+}
+
+func TestUnmarshalImplementation(t *testing.T) {
+	t.Parallel()
+
+	type config struct {
+		Storage storage `env:"BACKEND,default=disk"`
+	}
+	loader := func(key string) string {
+		return map[string]string{"BACKEND": "s3"}[key]
+	}
+
+	var cfg config
+	if err := env.Unmarshal(context.Background(), &cfg, env.WithEnvLoader(loader)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Storage == nil || cfg.Storage.Name() != "s3" {
+		t.Logf("unexpected implementation: %v", cfg.Storage)
+		t.Fail()
+	}
+}
+
+func TestUnmarshalWithProvenance(t *testing.T) {
+	t.Parallel()
+
+	type config struct {
+		Hosts    []string `env:"HOSTS"`
+		Port     int      `env:"PORT,default=8080"`
+		Retries  int      `env:"RETRIES"`
+		Database struct {
+			Name string `env:"DB_NAME"`
+		}
+	}
+	loader := func(key string) string {
+		return map[string]string{"HOSTS_0": "example.com", "RETRIES": "many", "DB_NAME": "app"}[key]
+	}
+
+	var cfg config
+	prov := map[string]env.Provenance{}
+	err := env.Unmarshal(context.Background(), &cfg, env.WithEnvLoader(loader), env.WithIndexedKeys("HOSTS_"),
+		env.WithFallbackToDefaultOnError(true), env.WithWarningHandler(func(env.Warning) {}), env.WithProvenance(prov))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string]env.Provenance{
+		"Hosts":         {Key: "HOSTS", Source: "HOSTS_0", RawLength: 11},
+		"Port":          {Key: "PORT", DefaultUsed: true},
+		"Retries":       {Key: "RETRIES", Source: "RETRIES", RawLength: 4, DefaultUsed: true},
+		"Database.Name": {Key: "DB_NAME", Source: "DB_NAME", RawLength: 3},
+	}
+	if len(prov) != len(expected) {
+		t.Fatalf("provenance (%v) does not match expected (%v)", prov, expected)
+	}
+	for field, want := range expected {
+		if got := prov[field]; got != want {
+			t.Logf("provenance of %s (%+v) does not match expected (%+v)", field, got, want)
+			t.Fail()
+		}
+	}
+}
+
+func TestParserReparse(t *testing.T) {
+	t.Parallel()
+
+	type config struct {
+		Port     int `env:"PORT"`
+		Database struct {
+			Host    string `env:"DB_HOST"`
+			MaxConn int    `env:"DB_MAX_CONN"`
+			Port    int    `env:"DB_PORT,immutable"`
+		}
+	}
+	vals := map[string]string{"PORT": "8080", "DB_HOST": "primary", "DB_MAX_CONN": "10"}
+	loader := func(key string) string {
+		return vals[key]
+	}
+	p, err := env.NewParser(env.WithEnvLoader(loader))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var cfg config
+	if err := p.Unmarshal(context.Background(), &cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	vals = map[string]string{"PORT": "9090", "DB_HOST": "replica", "DB_MAX_CONN": "20"}
+	if err := p.Reparse(context.Background(), &cfg, "DB_"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Port != 8080 || cfg.Database.Host != "replica" || cfg.Database.MaxConn != 20 {
+		t.Logf("unexpected config after reparse: %+v", cfg)
+		t.Fail()
+	}
+
+	vals = map[string]string{"DB_HOST": "other", "DB_MAX_CONN": "many"}
+	if err := p.Reparse(context.Background(), &cfg, "DB_"); err == nil {
+		t.Fatal("expected an error for an unparseable field")
+	}
+	if cfg.Database.Host != "replica" {
+		t.Logf("failed reparse partially applied: %+v", cfg)
+		t.Fail()
+	}
+
+	vals = map[string]string{"DB_HOST": "other", "DB_MAX_CONN": "20", "DB_PORT": "5433"}
+	if err := p.Reparse(context.Background(), &cfg, "DB_"); !errors.Is(err, env.ErrImmutableField) || !strings.Contains(err.Error(), "Database.Port (DB_PORT)") {
+		t.Logf("unexpected error: %v", err)
+		t.Fail()
+	}
+	if cfg.Database.Host != "replica" || cfg.Database.Port != 0 {
+		t.Logf("reparse applied despite an immutable change: %+v", cfg)
+		t.Fail()
+	}
+}
+
+func TestUnmarshalNamespace(t *testing.T) {
+	t.Parallel()
+
+	loader := func(key string) string {
+		return map[string]string{"MYAPP_DB_HOST": "db.internal", "HOST": "other"}[key]
+	}
+	p, err := env.NewParser(env.WithEnvLoader(loader))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var cfg struct {
+		Host string `env:"HOST"`
+	}
+	if err := p.Namespace("MYAPP_").Namespace("DB_").Unmarshal(context.Background(), &cfg); err != nil || cfg.Host != "db.internal" {
+		t.Logf("Unmarshal returned (%+v, %v)", cfg, err)
+		t.Fail()
+	}
+}
+
+func TestStrictUnknownKeys(t *testing.T) {
+	t.Parallel()
+
+	vals := map[string]string{"MYAPP_PORT": "8080", "MYAPP_HOST": "db", "MYAPP_PROT": "9090", "MYAPP_PEERS_0": "a", "OTHER": "x"}
+	loader := func(key string) string { return vals[key] }
+	lister := func() []string {
+		keys := make([]string, 0, len(vals))
+		for k := range vals {
+			keys = append(keys, k)
+		}
+		return keys
+	}
+	p, err := env.NewParser(env.WithEnvLoader(loader), env.WithKeyLister(lister), env.Strict())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var cfg struct {
+		Port int    `env:"PORT"`
+		Host string `env:"HOST"`
+	}
+
+	err = p.Namespace("MYAPP_").Unmarshal(context.Background(), &cfg, env.WithIndexedKeys("MYAPP_PEERS_"))
+	var errs env.Errors
+	if !errors.As(err, &errs) || len(errs) != 1 || !errors.Is(errs[0], env.ErrUnknownKey) || !strings.Contains(err.Error(), "MYAPP_PROT") {
+		t.Logf("unexpected error: %v", err)
+		t.Fail()
+	}
+	var global struct {
+		Port int `env:"MYAPP_PORT"`
+	}
+	if err := p.Unmarshal(context.Background(), &global); err != nil {
+		t.Logf("expected keys to be left alone without a namespace, got %v", err)
+		t.Fail()
+	}
+}
@@ -0,0 +1,487 @@
+package env
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+)
+
+type (
+	// checkFunc inspects a parsed value of envVar, returning the value to use in its place or an error rejecting it.
+	checkFunc func(o *envParseOpts, envVar string, v any) (any, error)
+
+	// valueCheck is a configured check along with a description of it, reported by Parser.Explain.
+	valueCheck struct {
+		desc string
+		fn   checkFunc
+	}
+)
+
+// WithValidation runs fn against each successfully parsed value of type T, e.g. to reject out of range ports or missing directories in the same call.
+// An error fails the parse as a ParseError naming the env var, and is subject to WithFallbackToDefaultOnError like any other parse failure.
+//
+// Values of any other type are not validated, so a Parser shared across types may carry validations for several of them. Defaults are never validated.
+// Validations run in the order provided.
+func WithValidation[T any](fn func(T) error) EnvParseOption {
+	return func(o *envParseOpts) error {
+		if fn == nil {
+			return errors.New("validation function cannot be nil")
+		}
+
+		o.addCheck("custom validation of "+typeName[T]()+" values", func(_ *envParseOpts, _ string, v any) (any, error) {
+			tv, ok := v.(T)
+			if !ok {
+				return v, nil
+			}
+			if err := fn(tv); err != nil {
+				return nil, fmt.Errorf("validation failed: %w", err)
+			}
+			return v, nil
+		})
+		return nil
+	}
+}
+
+// check runs the configured checks against v in order.
+func (o *envParseOpts) check(envVar string, v any) (any, error) {
+	for _, c := range o.checks {
+		var err error
+		if v, err = c.fn(o, envVar, v); err != nil {
+			return nil, err
+		}
+	}
+	return v, nil
+}
+
+// addCheck appends a check, described by desc, to those run against parsed values.
+func (o *envParseOpts) addCheck(desc string, fn checkFunc) {
+	o.checks = append(slices.Clip(o.checks), valueCheck{desc: desc, fn: fn})
+}
+
+// typeName returns the name of T, e.g. for describing checks that only apply to values of T.
+func typeName[T any]() string {
+	return strings.TrimPrefix(fmt.Sprintf("%T", (*T)(nil)), "*")
+}
+
+// WithMin rejects parsed values of type T below lower, or raises them to lower when WithClamping is set.
+// An integer or float bound applies to values of any integer or float type, e.g. WithMin(1) also bounds uint16 values, and to the items of slices of them.
+// Durations are only bounded by a time.Duration, e.g. WithMin(time.Second). Values of any other type are not checked.
+func WithMin[T cmp.Ordered](lower T) EnvParseOption {
+	return withBound(lower, -1, "below the minimum")
+}
+
+// WithMax rejects parsed values of type T above upper, or lowers them to upper when WithClamping is set. As with WithMin, an integer or float bound applies
+// to values and slice items of any integer or float type, while durations are only bounded by a time.Duration. Values of any other type are not checked.
+func WithMax[T cmp.Ordered](upper T) EnvParseOption {
+	return withBound(upper, 1, "above the maximum")
+}
+
+// WithRange rejects parsed values of type T, or items of a []T, outside of lower to upper inclusive, or clamps them to the nearest bound when WithClamping
+// is set. Unlike WithMin and WithMax it accepts types ordered by a Compare method, such as Date, TimeOfDay and time.Time. Values of any other type are not
+// checked.
+func WithRange[T interface{ Compare(T) int }](lower, upper T) EnvParseOption {
+	return func(o *envParseOpts) error {
+		if lower.Compare(upper) > 0 {
+			return fmt.Errorf("range lower bound %v is after upper bound %v", lower, upper)
+		}
+
+		inRange := func(parseOpts *envParseOpts, envVar string, val T) (T, error) {
+			bound := val
+			switch {
+			case val.Compare(lower) < 0:
+				bound = lower
+			case val.Compare(upper) > 0:
+				bound = upper
+			default:
+				return val, nil
+			}
+			if !parseOpts.clamp {
+				return val, fmt.Errorf("value %v is outside the range %v to %v", val, lower, upper)
+			}
+			parseOpts.warn(Warning{Kind: WarnClamped, EnvVar: envVar, Key: envVar})
+			return bound, nil
+		}
+		o.addCheck(fmt.Sprintf("range of %v to %v", lower, upper), func(parseOpts *envParseOpts, envVar string, v any) (any, error) {
+			switch tv := v.(type) {
+			case T:
+				return inRange(parseOpts, envVar, tv)
+			case []T:
+				out := make([]T, len(tv))
+				for i, item := range tv {
+					var err error
+					if out[i], err = inRange(parseOpts, envVar, item); err != nil {
+						return nil, fmt.Errorf("item (pos: %d): %w", i, err)
+					}
+				}
+				return out, nil
+			}
+			return v, nil
+		})
+		return nil
+	}
+}
+
+// WithClamping makes WithMin, WithMax and WithRange clamp out of range values to the bound rather than fail, reporting a WarnClamped warning.
+func WithClamping() EnvParseOption {
+	return func(o *envParseOpts) error {
+		o.clamp = true
+		return nil
+	}
+}
+
+// WithDurationGranularity rejects parsed durations, or items of a []time.Duration, that are not a multiple of granularity, e.g. `15ms` for a poller that
+// ticks in seconds, or rounds them to the nearest multiple when WithDurationRounding is set.
+func WithDurationGranularity(granularity time.Duration) EnvParseOption {
+	return func(o *envParseOpts) error {
+		if granularity <= 0 {
+			return errors.New("duration granularity must be positive")
+		}
+
+		o.addCheck(fmt.Sprintf("granularity of %v", granularity), func(parseOpts *envParseOpts, envVar string, v any) (any, error) {
+			switch tv := v.(type) {
+			case time.Duration:
+				return parseOpts.granular(envVar, tv, granularity)
+			case []time.Duration:
+				out := make([]time.Duration, len(tv))
+				for i, d := range tv {
+					var err error
+					if out[i], err = parseOpts.granular(envVar, d, granularity); err != nil {
+						return nil, fmt.Errorf("item (pos: %d): %w", i, err)
+					}
+				}
+				return out, nil
+			}
+			return v, nil
+		})
+		return nil
+	}
+}
+
+// WithDurationRounding makes WithDurationGranularity round durations to the nearest multiple of the granularity rather than fail, reporting a
+// WarnRounded warning.
+func WithDurationRounding() EnvParseOption {
+	return func(o *envParseOpts) error {
+		o.roundDurations = true
+		return nil
+	}
+}
+
+// granular checks that d is a multiple of granularity, rounding it when configured to.
+func (o *envParseOpts) granular(envVar string, d, granularity time.Duration) (time.Duration, error) {
+	if d%granularity == 0 {
+		return d, nil
+	}
+	if !o.roundDurations {
+		return 0, fmt.Errorf("duration %v is finer than the granularity of %v", d, granularity)
+	}
+	o.warn(Warning{Kind: WarnRounded, EnvVar: envVar, Key: envVar})
+	return d.Round(granularity), nil
+}
+
+// probabilitySumEpsilon is how far the weights checked by WithProbability may sum from 1, allowing for float rounding in values such as `0.1,0.2,0.7`.
+const probabilitySumEpsilon = 1e-6
+
+// WithProbability rejects parsed float32 and float64 values outside of 0 to 1, e.g. for sampling rates. The items of []float32, []float64 and
+// map[string]float64 values are weights, e.g. for traffic splits, which must each be within 0 to 1 and together sum to 1.
+// Values of any other type are not checked.
+func WithProbability() EnvParseOption {
+	return func(o *envParseOpts) error {
+		o.addCheck("probability between 0 and 1", func(_ *envParseOpts, _ string, v any) (any, error) {
+			switch tv := v.(type) {
+			case float32:
+				return v, checkProbability(float64(tv))
+			case float64:
+				return v, checkProbability(tv)
+			case []float32:
+				weights := make([]float64, len(tv))
+				for i, w := range tv {
+					weights[i] = float64(w)
+				}
+				return v, checkWeights(weights, func(i int) string { return fmt.Sprintf("item (pos: %d)", i) })
+			case []float64:
+				return v, checkWeights(tv, func(i int) string { return fmt.Sprintf("item (pos: %d)", i) })
+			case map[string]float64:
+				keys := make([]string, 0, len(tv))
+				for k := range tv {
+					keys = append(keys, k)
+				}
+				slices.Sort(keys)
+				weights := make([]float64, len(keys))
+				for i, k := range keys {
+					weights[i] = tv[k]
+				}
+				return v, checkWeights(weights, func(i int) string { return "key " + keys[i] })
+			}
+			return v, nil
+		})
+		return nil
+	}
+}
+
+// checkProbability rejects p outside of 0 to 1.
+func checkProbability(p float64) error {
+	if !(p >= 0 && p <= 1) {
+		return fmt.Errorf("value %v is not a probability between 0 and 1", p)
+	}
+	return nil
+}
+
+// checkWeights rejects weights that are not each a probability or do not sum to 1, naming an offending weight via describe.
+func checkWeights(weights []float64, describe func(i int) string) error {
+	sum := 0.0
+	for i, w := range weights {
+		if err := checkProbability(w); err != nil {
+			return fmt.Errorf("%s: %w", describe(i), err)
+		}
+		sum += w
+	}
+	if math.Abs(sum-1) > probabilitySumEpsilon {
+		return fmt.Errorf("weights sum to %v rather than 1", sum)
+	}
+	return nil
+}
+
+// WithAllowedValues rejects parsed values of type T, or items of a []T, that are not one of vals, listing the valid choices in the error.
+// Values of any other type are not checked.
+func WithAllowedValues[T comparable](vals ...T) EnvParseOption {
+	return func(o *envParseOpts) error {
+		if len(vals) == 0 {
+			return errors.New("allowed values cannot be empty")
+		}
+
+		allowed := slices.Clone(vals)
+		choices := make([]string, 0, len(allowed))
+		for _, a := range allowed {
+			choices = append(choices, fmt.Sprint(a))
+		}
+		notAllowed := func(val T) error {
+			return fmt.Errorf("value %v is not allowed (want one of: %s)", val, strings.Join(choices, ", "))
+		}
+		o.addCheck("allowed values: "+strings.Join(choices, ", "), func(_ *envParseOpts, _ string, v any) (any, error) {
+			switch tv := v.(type) {
+			case T:
+				if !slices.Contains(allowed, tv) {
+					return nil, notAllowed(tv)
+				}
+			case []T:
+				for i, item := range tv {
+					if !slices.Contains(allowed, item) {
+						return nil, fmt.Errorf("item (pos: %d): %w", i, notAllowed(item))
+					}
+				}
+			}
+			return v, nil
+		})
+		return nil
+	}
+}
+
+// withBound adds a check rejecting values that compare to bound as outside, i.e. -1 for a minimum and 1 for a maximum.
+// Values of T, and of any other integer or float type when T is one, are checked, as are the items of slices of them.
+func withBound[T cmp.Ordered](bound T, outside int, desc string) EnvParseOption {
+	return func(o *envParseOpts) error {
+		nb, numericBound := toNumeric(bound)
+		checkDesc := fmt.Sprintf("maximum of %v", bound)
+		if outside < 0 {
+			checkDesc = fmt.Sprintf("minimum of %v", bound)
+		}
+		// bounded checks a single value, which is returned unchanged if the bound does not apply to its type
+		bounded := func(parseOpts *envParseOpts, envVar string, v any) (any, error) {
+			var clamped any = bound
+			if tv, ok := v.(T); ok {
+				if cmp.Compare(tv, bound) != outside {
+					return v, nil
+				}
+			} else if nv, ok := toNumeric(v); ok && numericBound {
+				if nv.compare(nb) != outside {
+					return v, nil
+				}
+				clamped, ok = nb.convert(v)
+				if !ok && parseOpts.clamp {
+					return nil, fmt.Errorf("bound %v cannot be represented as %T", bound, v)
+				}
+			} else {
+				return v, nil
+			}
+
+			if !parseOpts.clamp {
+				return nil, fmt.Errorf("value %v is %s of %v", v, desc, bound)
+			}
+			parseOpts.warn(Warning{Kind: WarnClamped, EnvVar: envVar, Key: envVar})
+			return clamped, nil
+		}
+		o.addCheck(checkDesc, func(parseOpts *envParseOpts, envVar string, v any) (any, error) {
+			item := func(v any) (any, error) { return bounded(parseOpts, envVar, v) }
+			switch tv := v.(type) {
+			case []T:
+				return boundItems(tv, item)
+			case []int:
+				return boundItems(tv, item)
+			case []int8:
+				return boundItems(tv, item)
+			case []int16:
+				return boundItems(tv, item)
+			case []int32:
+				return boundItems(tv, item)
+			case []int64:
+				return boundItems(tv, item)
+			case []uint:
+				return boundItems(tv, item)
+			case []uint16:
+				return boundItems(tv, item)
+			case []uint32:
+				return boundItems(tv, item)
+			case []uint64:
+				return boundItems(tv, item)
+			case []float32:
+				return boundItems(tv, item)
+			case []float64:
+				return boundItems(tv, item)
+			}
+			return item(v)
+		})
+		return nil
+	}
+}
+
+// boundItems runs bounded against each item, returning a copy holding the results.
+func boundItems[E any](items []E, bounded func(any) (any, error)) (any, error) {
+	out := make([]E, len(items))
+	for i, item := range items {
+		v, err := bounded(item)
+		if err != nil {
+			return nil, fmt.Errorf("item (pos: %d): %w", i, err)
+		}
+		out[i] = v.(E)
+	}
+	return out, nil
+}
+
+// numeric is a value of any of the builtin integer or float types, widened so that values of different types can be compared exactly.
+// time.Duration is deliberately excluded, as a bare number bounding a duration would be read as nanoseconds.
+type numeric struct {
+	kind numericKind
+	i    int64
+	u    uint64
+	f    float64
+}
+
+type numericKind uint8
+
+const (
+	signedKind numericKind = iota
+	unsignedKind
+	floatKind
+)
+
+// toNumeric widens v, reporting false if it is not numeric.
+func toNumeric(v any) (numeric, bool) {
+	switch n := v.(type) {
+	case int:
+		return numeric{kind: signedKind, i: int64(n)}, true
+	case int8:
+		return numeric{kind: signedKind, i: int64(n)}, true
+	case int16:
+		return numeric{kind: signedKind, i: int64(n)}, true
+	case int32:
+		return numeric{kind: signedKind, i: int64(n)}, true
+	case int64:
+		return numeric{kind: signedKind, i: n}, true
+	case uint:
+		return numeric{kind: unsignedKind, u: uint64(n)}, true
+	case uint8:
+		return numeric{kind: unsignedKind, u: uint64(n)}, true
+	case uint16:
+		return numeric{kind: unsignedKind, u: uint64(n)}, true
+	case uint32:
+		return numeric{kind: unsignedKind, u: uint64(n)}, true
+	case uint64:
+		return numeric{kind: unsignedKind, u: n}, true
+	case float32:
+		return numeric{kind: floatKind, f: float64(n)}, true
+	case float64:
+		return numeric{kind: floatKind, f: n}, true
+	}
+	return numeric{}, false
+}
+
+// compare returns -1, 0 or 1 as n is less than, equal to or greater than other.
+func (n numeric) compare(other numeric) int {
+	switch {
+	case n.kind == floatKind || other.kind == floatKind:
+		return cmp.Compare(n.float(), other.float())
+	case n.kind == other.kind && n.kind == signedKind:
+		return cmp.Compare(n.i, other.i)
+	case n.kind == other.kind:
+		return cmp.Compare(n.u, other.u)
+	case n.kind == signedKind && n.i < 0:
+		return -1
+	case n.kind == signedKind:
+		return cmp.Compare(uint64(n.i), other.u)
+	case other.i < 0:
+		return 1
+	default:
+		return cmp.Compare(n.u, uint64(other.i))
+	}
+}
+
+func (n numeric) float() float64 {
+	switch n.kind {
+	case signedKind:
+		return float64(n.i)
+	case unsignedKind:
+		return float64(n.u)
+	}
+	return n.f
+}
+
+// convert returns n as a value of the same type as like, reporting false if it cannot be represented exactly.
+func (n numeric) convert(like any) (any, bool) {
+	i, u := n.i, n.u
+	switch n.kind {
+	case signedKind:
+		u = uint64(n.i)
+	case unsignedKind:
+		i = int64(n.u)
+	case floatKind:
+		i, u = int64(n.f), uint64(n.f)
+	}
+
+	var out any
+	switch like.(type) {
+	case int:
+		out = int(i)
+	case int8:
+		out = int8(i)
+	case int16:
+		out = int16(i)
+	case int32:
+		out = int32(i)
+	case int64:
+		out = i
+	case uint:
+		out = uint(u)
+	case uint8:
+		out = uint8(u)
+	case uint16:
+		out = uint16(u)
+	case uint32:
+		out = uint32(u)
+	case uint64:
+		out = u
+	case float32:
+		// floats need not represent the bound exactly
+		return float32(n.float()), true
+	case float64:
+		return n.float(), true
+	default:
+		return nil, false
+	}
+	// converting back detects bounds that were truncated or wrapped
+	back, _ := toNumeric(out)
+	return out, back.compare(n) == 0
+}
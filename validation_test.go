@@ -0,0 +1,308 @@
+package env_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ndisidore/go-env"
+)
+
+func TestWithValidation(t *testing.T) {
+	t.Parallel()
+
+	loader := func(key string) string {
+		return map[string]string{"PORT": "8080", "PRIVILEGED_PORT": "80", "NAME": "svc"}[key]
+	}
+	unprivileged := env.WithValidation(func(port int) error {
+		if port < 1024 {
+			return errors.New("port must be unprivileged")
+		}
+		return nil
+	})
+	cases := []struct {
+		searchEnv           string
+		options             []env.EnvParseOption
+		expected            int
+		expectedErrContains string
+	}{
+		{searchEnv: "PORT", expected: 8080},
+		{searchEnv: "PRIVILEGED_PORT", expectedErrContains: "PRIVILEGED_PORT to int: validation failed: port must be unprivileged"},
+		{searchEnv: "PRIVILEGED_PORT", options: []env.EnvParseOption{env.WithFallbackToDefaultOnError(true)}, expected: 9000},
+		{searchEnv: "UNSET", expected: 9000},
+		{searchEnv: "PORT", options: []env.EnvParseOption{env.WithValidation(func(string) error { return errors.New("not an int") })}, expected: 8080},
+	}
+	for _, tt := range cases {
+		t.Run(tt.searchEnv, func(t *testing.T) {
+			t.Parallel()
+			ret, err := env.FromEnvOrDefault(context.Background(), tt.searchEnv, 9000, append(tt.options, env.WithEnvLoader(loader), unprivileged)...)
+			switch {
+			case err != nil && tt.expectedErrContains != "":
+				if !strings.Contains(err.Error(), tt.expectedErrContains) {
+					t.Logf("unexpected error: %v", err)
+					t.Fail()
+				}
+			case err != nil:
+				t.Logf("unexpected error: %v", err)
+				t.Fail()
+			case tt.expectedErrContains != "":
+				t.Logf("expected error containing %q", tt.expectedErrContains)
+				t.Fail()
+			case ret != tt.expected:
+				t.Logf("return value (%d) does not match expected (%d)", ret, tt.expected)
+				t.Fail()
+			}
+		})
+	}
+
+	if ret, err := env.FromEnvOrDefault(context.Background(), "NAME", "", env.WithEnvLoader(loader), unprivileged); err != nil || ret != "svc" {
+		t.Logf("validations of other types should not apply, got (%q, %v)", ret, err)
+		t.Fail()
+	}
+	if _, err := env.FromEnvOrDefault(context.Background(), "PORT", 0, env.WithValidation[int](nil)); err == nil {
+		t.Log("expected an error for a nil validation")
+		t.Fail()
+	}
+}
+
+func TestWithMinMax(t *testing.T) {
+	t.Parallel()
+
+	loader := func(key string) string {
+		return map[string]string{"WORKERS": "8", "TOO_FEW": "0", "TOO_MANY": "512", "TIMEOUT": "10m"}[key]
+	}
+	bounds := []env.EnvParseOption{env.WithEnvLoader(loader), env.WithMin(1), env.WithMax(64)}
+	cases := []struct {
+		searchEnv           string
+		options             []env.EnvParseOption
+		expected            int
+		expectedErrContains string
+		expectedWarnings    int
+	}{
+		{searchEnv: "WORKERS", expected: 8},
+		{searchEnv: "TOO_FEW", expectedErrContains: "value 0 is below the minimum of 1"},
+		{searchEnv: "TOO_MANY", expectedErrContains: "value 512 is above the maximum of 64"},
+		{searchEnv: "TOO_FEW", options: []env.EnvParseOption{env.WithClamping()}, expected: 1, expectedWarnings: 1},
+		{searchEnv: "TOO_MANY", options: []env.EnvParseOption{env.WithClamping()}, expected: 64, expectedWarnings: 1},
+	}
+	for _, tt := range cases {
+		t.Run(tt.searchEnv, func(t *testing.T) {
+			t.Parallel()
+			var warnings []env.Warning
+			handler := env.WithWarningHandler(func(w env.Warning) { warnings = append(warnings, w) })
+			ret, err := env.FromEnvOrDefault(context.Background(), tt.searchEnv, 4, append(append(tt.options, handler), bounds...)...)
+			switch {
+			case err != nil && tt.expectedErrContains != "":
+				if !strings.Contains(err.Error(), tt.expectedErrContains) {
+					t.Logf("unexpected error: %v", err)
+					t.Fail()
+				}
+			case err != nil:
+				t.Logf("unexpected error: %v", err)
+				t.Fail()
+			case tt.expectedErrContains != "":
+				t.Logf("expected error containing %q", tt.expectedErrContains)
+				t.Fail()
+			case ret != tt.expected:
+				t.Logf("return value (%d) does not match expected (%d)", ret, tt.expected)
+				t.Fail()
+			}
+			if len(warnings) != tt.expectedWarnings || (len(warnings) > 0 && warnings[0].Kind != env.WarnClamped) {
+				t.Logf("unexpected warnings: %v", warnings)
+				t.Fail()
+			}
+		})
+	}
+
+	if _, err := env.FromEnvOrDefault(context.Background(), "TIMEOUT", time.Duration(0), env.WithEnvLoader(loader), env.WithMax(5*time.Minute)); err == nil || !strings.Contains(err.Error(), "value 10m0s is above the maximum of 5m0s") {
+		t.Logf("unexpected error: %v", err)
+		t.Fail()
+	}
+}
+
+func TestWithMinMaxAcrossTypes(t *testing.T) {
+	t.Parallel()
+
+	loader := func(key string) string {
+		return map[string]string{"WORKERS": "0", "SHARDS": "70000", "TIMEOUT": "10m", "RATIO": "1.5", "PORTS": "80,0,443", "LIMITS": "5,100", "NEGATIVE": "-3"}[key]
+	}
+	ctx := context.Background()
+	bounds := []env.EnvParseOption{env.WithEnvLoader(loader), env.WithMin(1), env.WithMax(1024)}
+
+	if _, err := env.FromEnvOrDefault(ctx, "WORKERS", uint16(4), bounds...); err == nil || !strings.Contains(err.Error(), "value 0 is below the minimum of 1") {
+		t.Logf("expected an int bound to apply to a uint16, got %v", err)
+		t.Fail()
+	}
+	if ret, err := env.FromEnvOrDefault(ctx, "WORKERS", uint16(4), append(bounds, env.WithClamping())...); err != nil || ret != 1 {
+		t.Logf("FromEnvOrDefault returned (%d, %v)", ret, err)
+		t.Fail()
+	}
+	if _, err := env.FromEnvOrDefault(ctx, "SHARDS", int64(0), bounds...); err == nil || !strings.Contains(err.Error(), "value 70000 is above the maximum of 1024") {
+		t.Logf("expected an int bound to apply to an int64, got %v", err)
+		t.Fail()
+	}
+	if _, err := env.FromEnvOrDefault(ctx, "NEGATIVE", int8(0), bounds...); err == nil || !strings.Contains(err.Error(), "below the minimum") {
+		t.Logf("expected an int bound to apply to an int8, got %v", err)
+		t.Fail()
+	}
+	// a bare number does not bound durations, which would otherwise be read as nanoseconds
+	if ret, err := env.FromEnvOrDefault(ctx, "TIMEOUT", time.Duration(0), bounds...); err != nil || ret != 10*time.Minute {
+		t.Logf("FromEnvOrDefault returned (%v, %v)", ret, err)
+		t.Fail()
+	}
+	if _, err := env.FromEnvOrDefault(ctx, "TIMEOUT", []time.Duration{}, append(bounds, env.WithMin(time.Hour))...); err == nil || !strings.Contains(err.Error(), "item (pos: 0): value 10m0s is below the minimum of 1h0m0s") {
+		t.Logf("expected a duration bound to apply to duration items, got %v", err)
+		t.Fail()
+	}
+	if ret, err := env.FromEnvOrDefault(ctx, "RATIO", float32(0), env.WithEnvLoader(loader), env.WithMax(1), env.WithClamping()); err != nil || ret != 1 {
+		t.Logf("FromEnvOrDefault returned (%v, %v)", ret, err)
+		t.Fail()
+	}
+
+	// a bound the destination cannot hold can still reject values, but not clamp them
+	if _, err := env.FromEnvOrDefault(ctx, "WORKERS", uint8(4), env.WithEnvLoader(loader), env.WithMin(300)); err == nil || !strings.Contains(err.Error(), "below the minimum of 300") {
+		t.Logf("unexpected error: %v", err)
+		t.Fail()
+	}
+	if _, err := env.FromEnvOrDefault(ctx, "WORKERS", uint8(4), env.WithEnvLoader(loader), env.WithMin(300), env.WithClamping()); err == nil || !strings.Contains(err.Error(), "bound 300 cannot be represented as uint8") {
+		t.Logf("unexpected error: %v", err)
+		t.Fail()
+	}
+	if ret, err := env.FromEnvOrDefault(ctx, "NEGATIVE", uint16(4), env.WithEnvLoader(func(string) string { return "3" }), env.WithMin(-1)); err != nil || ret != 3 {
+		t.Logf("FromEnvOrDefault returned (%d, %v)", ret, err)
+		t.Fail()
+	}
+
+	if _, err := env.FromEnvOrDefault(ctx, "PORTS", []uint16{}, bounds...); err == nil || !strings.Contains(err.Error(), "item (pos: 1): value 0 is below the minimum of 1") {
+		t.Logf("expected bounds to apply to slice items, got %v", err)
+		t.Fail()
+	}
+	if ret, err := env.FromEnvOrDefault(ctx, "LIMITS", []int{}, append(bounds, env.WithMax(50), env.WithClamping())...); err != nil || !reflect.DeepEqual(ret, []int{5, 50}) {
+		t.Logf("FromEnvOrDefault returned (%v, %v)", ret, err)
+		t.Fail()
+	}
+
+	// values that are not numeric are not checked by a numeric bound
+	if ret, err := env.FromEnvOrDefault(ctx, "TIMEOUT", "", bounds...); err != nil || ret != "10m" {
+		t.Logf("FromEnvOrDefault returned (%q, %v)", ret, err)
+		t.Fail()
+	}
+}
+
+func TestWithDurationGranularity(t *testing.T) {
+	t.Parallel()
+
+	loader := func(key string) string {
+		return map[string]string{"POLL": "5s", "FINE_POLL": "1500ms", "POLLS": "1s,2m", "FINE_POLLS": "1s,15ms"}[key]
+	}
+	seconds := env.WithDurationGranularity(time.Second)
+
+	if ret, err := env.FromEnvOrDefault(context.Background(), "POLL", time.Duration(0), env.WithEnvLoader(loader), seconds); err != nil || ret != 5*time.Second {
+		t.Logf("FromEnvOrDefault returned (%v, %v)", ret, err)
+		t.Fail()
+	}
+	if ret, err := env.FromEnvOrDefault(context.Background(), "POLLS", []time.Duration{}, env.WithEnvLoader(loader), seconds); err != nil || !reflect.DeepEqual(ret, []time.Duration{time.Second, 2 * time.Minute}) {
+		t.Logf("FromEnvOrDefault returned (%v, %v)", ret, err)
+		t.Fail()
+	}
+	if _, err := env.FromEnvOrDefault(context.Background(), "FINE_POLL", time.Duration(0), env.WithEnvLoader(loader), seconds); err == nil || !strings.Contains(err.Error(), "duration 1.5s is finer than the granularity of 1s") {
+		t.Logf("unexpected error: %v", err)
+		t.Fail()
+	}
+	if _, err := env.FromEnvOrDefault(context.Background(), "FINE_POLLS", []time.Duration{}, env.WithEnvLoader(loader), seconds); err == nil || !strings.Contains(err.Error(), "item (pos: 1): duration 15ms") {
+		t.Logf("unexpected error: %v", err)
+		t.Fail()
+	}
+
+	var warnings []env.Warning
+	ret, err := env.FromEnvOrDefault(context.Background(), "FINE_POLL", time.Duration(0), env.WithEnvLoader(loader), seconds, env.WithDurationRounding(),
+		env.WithWarningHandler(func(w env.Warning) { warnings = append(warnings, w) }))
+	if err != nil || ret != 2*time.Second {
+		t.Logf("FromEnvOrDefault returned (%v, %v)", ret, err)
+		t.Fail()
+	}
+	if len(warnings) != 1 || warnings[0].Kind != env.WarnRounded {
+		t.Logf("unexpected warnings: %v", warnings)
+		t.Fail()
+	}
+	if _, err := env.FromEnvOrDefault(context.Background(), "POLL", time.Duration(0), env.WithDurationGranularity(0)); err == nil {
+		t.Log("expected an error for a zero granularity")
+		t.Fail()
+	}
+}
+
+func TestWithProbability(t *testing.T) {
+	t.Parallel()
+
+	loader := func(key string) string {
+		return map[string]string{"RATE": "0.25", "SPLIT": "0.1,0.2,0.7", "MAP_SPLIT": "a=0.5,b=0.5", "BAD_RATE": "1.5", "SHORT_SPLIT": "0.5,0.4", "NEG_SPLIT": "a=1.5,b=-0.5"}[key]
+	}
+	ctx := context.Background()
+
+	if ret, err := env.FromEnvOrDefault(ctx, "RATE", 0.0, env.WithEnvLoader(loader), env.WithProbability()); err != nil || ret != 0.25 {
+		t.Logf("FromEnvOrDefault returned (%v, %v)", ret, err)
+		t.Fail()
+	}
+	if _, err := env.FromEnvOrDefault(ctx, "SPLIT", []float64{}, env.WithEnvLoader(loader), env.WithProbability()); err != nil {
+		t.Logf("unexpected error: %v", err)
+		t.Fail()
+	}
+	if _, err := env.FromEnvOrDefault(ctx, "SPLIT", []float32{}, env.WithEnvLoader(loader), env.WithProbability()); err != nil {
+		t.Logf("unexpected error: %v", err)
+		t.Fail()
+	}
+	if _, err := env.FromEnvOrDefault(ctx, "MAP_SPLIT", map[string]float64{}, env.WithEnvLoader(loader), env.WithProbability()); err != nil {
+		t.Logf("unexpected error: %v", err)
+		t.Fail()
+	}
+
+	if _, err := env.FromEnvOrDefault(ctx, "BAD_RATE", float32(0), env.WithEnvLoader(loader), env.WithProbability()); err == nil || !strings.Contains(err.Error(), "value 1.5 is not a probability between 0 and 1") {
+		t.Logf("unexpected error: %v", err)
+		t.Fail()
+	}
+	if _, err := env.FromEnvOrDefault(ctx, "SHORT_SPLIT", []float64{}, env.WithEnvLoader(loader), env.WithProbability()); err == nil || !strings.Contains(err.Error(), "weights sum to 0.9 rather than 1") {
+		t.Logf("unexpected error: %v", err)
+		t.Fail()
+	}
+	if _, err := env.FromEnvOrDefault(ctx, "NEG_SPLIT", map[string]float64{}, env.WithEnvLoader(loader), env.WithProbability()); err == nil || !strings.Contains(err.Error(), "key a: value 1.5 is not a probability") {
+		t.Logf("unexpected error: %v", err)
+		t.Fail()
+	}
+}
+
+func TestWithAllowedValues(t *testing.T) {
+	t.Parallel()
+
+	loader := func(key string) string {
+		return map[string]string{"MODE": "fast", "BAD_MODE": "turbo", "MODES": "fast,safe", "BAD_MODES": "fast,turbo"}[key]
+	}
+	modes := env.WithAllowedValues("fast", "safe")
+
+	if ret, err := env.FromEnvOrDefault(context.Background(), "MODE", "safe", env.WithEnvLoader(loader), modes); err != nil || ret != "fast" {
+		t.Logf("FromEnvOrDefault returned (%q, %v)", ret, err)
+		t.Fail()
+	}
+	if _, err := env.FromEnvOrDefault(context.Background(), "BAD_MODE", "safe", env.WithEnvLoader(loader), modes); err == nil || !strings.Contains(err.Error(), "value turbo is not allowed (want one of: fast, safe)") {
+		t.Logf("unexpected error: %v", err)
+		t.Fail()
+	}
+	if ret, err := env.FromEnvOrDefault(context.Background(), "BAD_MODE", "safe", env.WithEnvLoader(loader), modes, env.WithFallbackToDefaultOnError(true)); err != nil || ret != "safe" {
+		t.Logf("expected the default, got (%q, %v)", ret, err)
+		t.Fail()
+	}
+	if ret, err := env.FromEnvOrDefault(context.Background(), "MODES", []string{}, env.WithEnvLoader(loader), modes); err != nil || len(ret) != 2 {
+		t.Logf("FromEnvOrDefault returned (%q, %v)", ret, err)
+		t.Fail()
+	}
+	if _, err := env.FromEnvOrDefault(context.Background(), "BAD_MODES", []string{}, env.WithEnvLoader(loader), modes); err == nil || !strings.Contains(err.Error(), "item (pos: 1): value turbo is not allowed") {
+		t.Logf("unexpected error: %v", err)
+		t.Fail()
+	}
+	if _, err := env.FromEnvOrDefault(context.Background(), "MODE", "", env.WithAllowedValues[string]()); err == nil {
+		t.Log("expected an error for empty allowed values")
+		t.Fail()
+	}
+}
@@ -0,0 +1,70 @@
+package env
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+)
+
+// WithVariantKey enables percentage-based values of the form `A@90%,B@10%`, so that a fraction of instances or requests resolve to an alternate value.
+// Variants are separated by the parser's separator (see WithEnvParseSeparator).
+//
+// The variant is chosen by hashing the env var together with the key returned by fn, e.g. an instance or request id, so the same key always selects
+// the same variant. Weights must sum to 100. Values without a trailing `@N%` are used as-is.
+func WithVariantKey(fn func(ctx context.Context) string) EnvParseOption {
+	return func(o *envParseOpts) error {
+		if fn == nil {
+			return errors.New("variant key function cannot be nil")
+		}
+
+		o.variantKey = fn
+		return nil
+	}
+}
+
+// selectVariant picks the variant of a weighted value, whose variants are separated by sep, for the hash input, returning the value unchanged if it is not weighted.
+func selectVariant(in, sep, hashInput string) (string, error) {
+	if !strings.HasSuffix(in, "%") || !strings.Contains(in, "@") {
+		return in, nil
+	}
+
+	type variant struct {
+		value  string
+		weight int
+	}
+	var (
+		variants []variant
+		total    int
+	)
+	for i, item := range strings.Split(in, sep) {
+		item = strings.TrimSpace(item)
+		at := strings.LastIndex(item, "@")
+		if at < 0 || !strings.HasSuffix(item, "%") {
+			return "", fmt.Errorf("variant %s (pos: %d) is missing an @N%% weight", item, i)
+		}
+		weight, err := strconv.Atoi(item[at+1 : len(item)-1])
+		if err != nil || weight < 0 {
+			return "", fmt.Errorf("variant %s (pos: %d) has an invalid weight", item, i)
+		}
+		variants = append(variants, variant{value: item[:at], weight: weight})
+		total += weight
+	}
+	if total != 100 {
+		return "", fmt.Errorf("variant weights sum to %d%%, expected 100%%", total)
+	}
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(hashInput))
+	bucket := int(h.Sum32() % 100)
+	for _, v := range variants {
+		if bucket < v.weight {
+			return v.value, nil
+		}
+		bucket -= v.weight
+	}
+	// unreachable as the weights sum to 100
+	return variants[len(variants)-1].value, nil
+}
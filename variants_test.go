@@ -0,0 +1,75 @@
+package env_test
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/ndisidore/go-env"
+)
+
+type variantKeyCtx struct{}
+
+func TestWithVariantKey(t *testing.T) {
+	t.Parallel()
+
+	loader := func(key string) string {
+		return map[string]string{"BACKEND": "stable@90%,canary@10%", "PLAIN": "stable", "ALL": "canary@100%", "BAD_SUM": "a@50%,b@40%", "BAD_WEIGHT": "a@x%"}[key]
+	}
+	keyFromCtx := func(ctx context.Context) string {
+		id, _ := ctx.Value(variantKeyCtx{}).(string)
+		return id
+	}
+
+	counts := map[string]int{}
+	for i := 0; i < 1000; i++ {
+		ctx := context.WithValue(context.Background(), variantKeyCtx{}, "instance-"+strconv.Itoa(i))
+		ret, err := env.FromEnvOrDefault(ctx, "BACKEND", "", env.WithEnvLoader(loader), env.WithVariantKey(keyFromCtx))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		counts[ret]++
+
+		again, _ := env.FromEnvOrDefault(ctx, "BACKEND", "", env.WithEnvLoader(loader), env.WithVariantKey(keyFromCtx))
+		if again != ret {
+			t.Fatalf("variant selection is not stable for instance-%d: %s then %s", i, ret, again)
+		}
+	}
+	if len(counts) != 2 || counts["canary"] < 50 || counts["canary"] > 150 {
+		t.Logf("unexpected variant distribution: %v", counts)
+		t.Fail()
+	}
+
+	cases := []struct {
+		searchEnv           string
+		expected            string
+		expectedErrContains string
+	}{
+		{searchEnv: "PLAIN", expected: "stable"},
+		{searchEnv: "ALL", expected: "canary"},
+		{searchEnv: "BAD_SUM", expectedErrContains: "sum to 90%"},
+		{searchEnv: "BAD_WEIGHT", expectedErrContains: "invalid weight"},
+	}
+	for _, tt := range cases {
+		ret, err := env.FromEnvOrDefault(context.Background(), tt.searchEnv, "", env.WithEnvLoader(loader), env.WithVariantKey(keyFromCtx))
+		switch {
+		case err != nil && tt.expectedErrContains != "":
+			if !strings.Contains(err.Error(), tt.expectedErrContains) {
+				t.Logf("unexpected error: %v", err)
+				t.Fail()
+			}
+		case err != nil:
+			t.Logf("unexpected error: %v", err)
+			t.Fail()
+		case ret != tt.expected:
+			t.Logf("return value (%s) does not match expected (%s)", ret, tt.expected)
+			t.Fail()
+		}
+	}
+
+	if ret, err := env.FromEnvOrDefault(context.Background(), "SEMI", "", env.WithEnvLoader(func(string) string { return "a,b@100%;c@0%" }), env.WithVariantKey(keyFromCtx), env.WithEnvParseSeparator(";")); err != nil || ret != "a,b" {
+		t.Logf("expected variants to be split on the configured separator, got (%q, %v)", ret, err)
+		t.Fail()
+	}
+}
@@ -0,0 +1,78 @@
+package env
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+)
+
+type (
+	// WarningKind classifies a non-fatal finding reported to a WarningHandler.
+	WarningKind string
+
+	// Warning is a non-fatal finding encountered while parsing. Unlike an error, the parse still succeeds.
+	Warning struct {
+		Kind WarningKind
+		// EnvVar is the env var being parsed.
+		EnvVar string
+		// Key is the key the finding relates to, which differs from EnvVar for deprecated and indexed keys.
+		Key string
+		// Err is the parse error that was swallowed, set only for WarnDefaultOnError.
+		Err error
+	}
+
+	// WarningHandler receives warnings. It may be called concurrently when a Parser is shared.
+	WarningHandler func(w Warning)
+)
+
+const (
+	// WarnDeprecatedKey is reported when a value is read from a key passed to WithDeprecatedKey.
+	WarnDeprecatedKey WarningKind = "deprecated_key"
+	// WarnBlankIsUnset is reported when a whitespace-only value is treated as unset due to WithBlankIsUnset.
+	WarnBlankIsUnset WarningKind = "blank_is_unset"
+	// WarnNormalized is reported when a byte order mark or trailing carriage return is stripped from a value.
+	WarnNormalized WarningKind = "normalized"
+	// WarnDefaultOnError is reported when a value fails to parse and the default is used due to WithFallbackToDefaultOnError.
+	WarnDefaultOnError WarningKind = "default_on_error"
+	// WarnClamped is reported when a value outside the range set by WithMin, WithMax or WithRange is clamped to it due to WithClamping.
+	WarnClamped WarningKind = "clamped"
+	// WarnRounded is reported when a duration finer than the granularity set by WithDurationGranularity is rounded due to WithDurationRounding.
+	WarnRounded WarningKind = "rounded"
+	// WarnWorldWritable is reported when a file mode allows anyone to write due to WithWorldWritableWarning.
+	WarnWorldWritable WarningKind = "world_writable"
+)
+
+// WithWarningHandler registers a handler for non-fatal findings so they can be logged or counted separately from errors.
+//
+// When no handler is registered, deprecated keys are logged via slog and other warnings are discarded.
+func WithWarningHandler(handler WarningHandler) EnvParseOption {
+	return func(o *envParseOpts) error {
+		if handler == nil {
+			return errors.New("warning handler function cannot be nil")
+		}
+
+		o.warningHandler = handler
+		return nil
+	}
+}
+
+// warn reports w to the configured handler, if any.
+func (o *envParseOpts) warn(w Warning) {
+	if o.warningHandler != nil {
+		o.warningHandler(w)
+	}
+}
+
+// warnDeprecated reports use of a deprecated key, logging it when no handler is registered.
+func (o *envParseOpts) warnDeprecated(ctx context.Context, envVar, key string) {
+	if o.warningHandler == nil {
+		logDeprecated(ctx, envVar, key)
+		return
+	}
+	o.warningHandler(Warning{Kind: WarnDeprecatedKey, EnvVar: envVar, Key: key})
+}
+
+// logDeprecated logs use of a deprecated key. It is the default handling of WarnDeprecatedKey.
+func logDeprecated(ctx context.Context, envVar, key string) {
+	slog.Default().WarnContext(ctx, "deprecated env var in use", slog.String("env_var", key), slog.String("replacement", envVar))
+}
@@ -0,0 +1,67 @@
+package env_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/ndisidore/go-env"
+)
+
+func TestWithWarningHandler(t *testing.T) {
+	t.Parallel()
+
+	loader := func(key string) string {
+		return map[string]string{"OLD_HOST": "old", "BLANK": "  ", "CRLF": "value\r", "PORT": "abc"}[key]
+	}
+	cases := []struct {
+		searchEnv string
+		options   []env.EnvParseOption
+		expected  env.Warning
+	}{
+		{searchEnv: "HOST", options: []env.EnvParseOption{env.WithDeprecatedKey("OLD_HOST")}, expected: env.Warning{Kind: env.WarnDeprecatedKey, EnvVar: "HOST", Key: "OLD_HOST"}},
+		{searchEnv: "BLANK", options: []env.EnvParseOption{env.WithBlankIsUnset()}, expected: env.Warning{Kind: env.WarnBlankIsUnset, EnvVar: "BLANK", Key: "BLANK"}},
+		{searchEnv: "CRLF", expected: env.Warning{Kind: env.WarnNormalized, EnvVar: "CRLF", Key: "CRLF"}},
+		{searchEnv: "PORT", options: []env.EnvParseOption{env.WithFallbackToDefaultOnError(true)}, expected: env.Warning{Kind: env.WarnDefaultOnError, EnvVar: "PORT", Key: "PORT"}},
+	}
+	for _, tt := range cases {
+		t.Run(string(tt.expected.Kind), func(t *testing.T) {
+			var (
+				mu       sync.Mutex
+				warnings []env.Warning
+			)
+			handler := func(w env.Warning) {
+				mu.Lock()
+				defer mu.Unlock()
+				warnings = append(warnings, w)
+			}
+
+			var err error
+			if tt.searchEnv == "PORT" {
+				_, err = env.FromEnvOrDefault(context.Background(), tt.searchEnv, 0, append(tt.options, env.WithEnvLoader(loader), env.WithWarningHandler(handler))...)
+			} else {
+				_, err = env.FromEnvOrDefault(context.Background(), tt.searchEnv, "default", append(tt.options, env.WithEnvLoader(loader), env.WithWarningHandler(handler))...)
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(warnings) != 1 {
+				t.Fatalf("expected a single warning, got %v", warnings)
+			}
+			got := warnings[0]
+			if got.Kind != tt.expected.Kind || got.EnvVar != tt.expected.EnvVar || got.Key != tt.expected.Key {
+				t.Logf("warning (%+v) does not match expected (%+v)", got, tt.expected)
+				t.Fail()
+			}
+			if (got.Err != nil) != (tt.expected.Kind == env.WarnDefaultOnError) {
+				t.Logf("unexpected warning error: %v", got.Err)
+				t.Fail()
+			}
+		})
+	}
+
+	if _, err := env.FromEnvOrDefault(context.Background(), "HOST", "", env.WithWarningHandler(nil)); err == nil {
+		t.Log("expected an error for a nil warning handler")
+		t.Fail()
+	}
+}